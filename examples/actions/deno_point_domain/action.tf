@@ -0,0 +1,30 @@
+# Run with `terraform apply -invoke=action.deno_point_domain.rollback` to route
+# the domain back to the previous deployment without redeploying.
+
+action "deno_point_domain" "rollback" {
+  config {
+    domain_id     = deno_domain.example.id
+    deployment_id = var.previous_deployment_id
+  }
+}
+
+# Actions can also be triggered by the lifecycle of other resources, e.g. to
+# point the domain to every new deployment. Updating a deno_deployment creates
+# a new deployment as well, hence after_update.
+resource "deno_deployment" "example" {
+  # ...
+
+  lifecycle {
+    action_trigger {
+      events  = [after_create, after_update]
+      actions = [action.deno_point_domain.latest]
+    }
+  }
+}
+
+action "deno_point_domain" "latest" {
+  config {
+    domain_id     = deno_domain.example.id
+    deployment_id = deno_deployment.example.deployment_id
+  }
+}
@@ -0,0 +1,9 @@
+# Run with `terraform apply -invoke=action.deno_provision_certificate.example`,
+# e.g. to retry a failed provisioning.
+
+action "deno_provision_certificate" "example" {
+  config {
+    # Domain ownership verification must be completed to perform certificate provisioning.
+    domain_id = deno_domain.example.id
+  }
+}
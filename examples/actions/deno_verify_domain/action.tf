@@ -0,0 +1,11 @@
+# Run with `terraform apply -invoke=action.deno_verify_domain.example`, e.g.
+# after fixing the DNS records of the domain.
+
+action "deno_verify_domain" "example" {
+  config {
+    domain_id = deno_domain.example.id
+
+    # DNS propagation may take a while; the maximum time to wait can be specified.
+    timeout = "15m"
+  }
+}
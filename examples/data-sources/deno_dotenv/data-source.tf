@@ -0,0 +1,13 @@
+data "deno_dotenv" "production" {
+  # Variables in later files override the ones in earlier files.
+  files = ["${path.module}/.env", "${path.module}/.env.production"]
+}
+
+resource "deno_deployment" "example" {
+  project_id      = deno_project.my_project.id
+  entry_point_url = "main.ts"
+  assets          = data.deno_assets.my_assets.output
+  env_vars        = {}
+  # The output is marked as sensitive.
+  secret_env_vars = data.deno_dotenv.production.output
+}
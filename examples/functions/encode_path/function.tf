@@ -0,0 +1,4 @@
+# Returns "static/hello+world.txt"
+output "encoded" {
+  value = provider::deno::encode_path("static/hello world.txt")
+}
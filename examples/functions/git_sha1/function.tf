@@ -0,0 +1,4 @@
+# Pin the expected content of an asset
+output "main_sha1" {
+  value = provider::deno::git_sha1(file("${path.module}/src/main.ts"))
+}
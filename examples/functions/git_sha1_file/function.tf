@@ -0,0 +1,4 @@
+# Works with binary files as well
+output "logo_sha1" {
+  value = provider::deno::git_sha1_file("${path.module}/static/logo.png")
+}
@@ -0,0 +1,9 @@
+resource "deno_deployment" "example" {
+  project_id      = deno_project.example.id
+  entry_point_url = "main.ts"
+  assets = {
+    "main.ts"    = provider::deno::inline_asset("Deno.serve(() => new Response(\"Hello\"));", "utf-8")
+    "config.bin" = provider::deno::inline_asset(filebase64("${path.module}/config.bin"), "base64")
+  }
+  env_vars = {}
+}
@@ -14,4 +14,12 @@ provider "deno" {
   # Organization ID that this provider will interact with.
   # If omitted, the organization ID will be read from the environment variable `DENO_DEPLOY_ORGANIZATION_ID`.
   organization_id = "your_organization_id"
+
+  # Set to true to use only the local data sources and functions, e.g. to run
+  # `terraform validate` in CI jobs without credentials.
+  # offline = true
+
+  # Lower the rate of the API requests if large applies hit the rate limit.
+  # max_requests_per_second = 5
+  # max_concurrent_uploads  = 1
 }
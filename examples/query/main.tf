@@ -0,0 +1,9 @@
+terraform {
+  required_providers {
+    deno = {
+      source = "denoland/deno"
+    }
+  }
+}
+
+provider "deno" {}
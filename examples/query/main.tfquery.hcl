@@ -0,0 +1,36 @@
+# Run `terraform query -generate-config-out=generated.tf` (Terraform 1.14+) to
+# write `import` blocks and resource configuration for the listed objects.
+
+variable "project_id" {
+  type = string
+}
+
+list "deno_project" "apps" {
+  provider = deno
+
+  config {
+    name_prefix = "app-"
+  }
+}
+
+list "deno_domain" "example_com" {
+  provider = deno
+
+  config {
+    domain_suffix = "example.com"
+    validated     = true
+  }
+}
+
+# The generated configuration of a deployment lacks `entry_point_url` and the
+# assets, which the API doesn't return; fill them in before importing.
+list "deno_deployment" "latest" {
+  provider         = deno
+  include_resource = true
+  limit            = 5
+
+  config {
+    project_id = var.project_id
+    status     = "success"
+  }
+}
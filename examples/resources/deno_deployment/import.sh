@@ -0,0 +1,14 @@
+# Import a deployment by its ID. The API doesn't return the assets, entry point
+# and environment variables of a deployment, so the first apply after the
+# import creates a new deployment from the configuration.
+terraform import deno_deployment.example abcdefghijkl
+
+# With Terraform 1.12 and later, a deployment can also be imported by its
+# identity. `organization_id` defaults to the organization of the provider.
+#
+# import {
+#   to = deno_deployment.example
+#   identity = {
+#     deployment_id = "abcdefghijkl"
+#   }
+# }
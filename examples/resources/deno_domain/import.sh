@@ -0,0 +1,13 @@
+# Import a domain by its ID or by its name
+terraform import deno_domain.example 11111111-2222-3333-4444-555555555555
+terraform import deno_domain.example foo.example.com
+
+# With Terraform 1.12 and later, a domain can also be imported by its
+# identity. `organization_id` defaults to the organization of the provider.
+#
+# import {
+#   to = deno_domain.example
+#   identity = {
+#     domain = "foo.example.com"
+#   }
+# }
@@ -18,6 +18,10 @@ terraform {
 # Add a new domain to your organization.
 resource "deno_domain" "example" {
   domain = "foo.example.com"
+
+  # The DNS zone the records are added to. The names of the records in
+  # dns_records_for_each are relative to it.
+  zone = "example.com"
 }
 
 # Add DNS records to the nameserver.
@@ -48,6 +52,21 @@ resource "cloudflare_record" "my_record_2" {
   ttl     = 120
 }
 
+# Alternatively, the records can be added with for_each. Its keys are only
+# known once the domain is created, so the initial setup then needs
+# `terraform apply -target=deno_domain.example` first.
+#
+# resource "cloudflare_record" "my_record" {
+#   for_each = deno_domain.example.dns_records_for_each
+#
+#   zone_id = "<put your zone ID>"
+#   name    = each.value.name
+#   type    = each.value.type
+#   value   = each.value.content
+#   proxied = false
+#   ttl     = 120
+# }
+
 # Added custom domain needs to be verified for ownership.
 resource "deno_domain_verification" "example" {
   depends_on = [cloudflare_record.my_record_0, cloudflare_record.my_record_1, cloudflare_record.my_record_2]
@@ -65,4 +84,9 @@ resource "deno_domain_certificate" "example" {
   depends_on = [deno_domain_verification.example]
 
   domain_id = deno_domain.example.id
+
+  # Wait up to 15 minutes for the certificates to be issued.
+  timeouts = {
+    create = "15m"
+  }
 }
@@ -7,4 +7,12 @@ resource "deno_domain_certificate" "example" {
 
   # The domain to provision a certificate for.
   domain_id = deno_domain.example.id
+
+  # Plan a re-provision when a certificate expires within 30 days.
+  renew_before = "720h"
+
+  # How long to wait for the provisioning to finish. Defaults to 10 minutes.
+  timeouts = {
+    create = "15m"
+  }
 }
@@ -0,0 +1,12 @@
+# Import a project by its ID
+terraform import deno_project.example 11111111-2222-3333-4444-555555555555
+
+# With Terraform 1.12 and later, a project can also be imported by its
+# identity. `organization_id` defaults to the organization of the provider.
+#
+# import {
+#   to = deno_project.example
+#   identity = {
+#     id = "11111111-2222-3333-4444-555555555555"
+#   }
+# }
@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type progressKey struct{}
+
+// withProgress returns a context carrying the function that streams progress
+// messages of an action to Terraform.
+func withProgress(ctx context.Context, send func(action.InvokeProgressEvent)) context.Context {
+	return context.WithValue(ctx, progressKey{}, send)
+}
+
+// reportProgress sends a progress message if the context carries a progress
+// function, i.e. when called from an action. It does nothing otherwise.
+func reportProgress(ctx context.Context, format string, args ...any) {
+	send, ok := ctx.Value(progressKey{}).(func(action.InvokeProgressEvent))
+	if !ok || send == nil {
+		return
+	}
+	send(action.InvokeProgressEvent{Message: fmt.Sprintf(format, args...)})
+}
+
+// parseActionTimeout parses the timeout attribute of an action, returning the
+// default if it is omitted.
+func parseActionTimeout(value types.String, defaultTimeout time.Duration) (time.Duration, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if value.IsNull() || value.IsUnknown() {
+		return defaultTimeout, diags
+	}
+
+	timeout, err := time.ParseDuration(value.ValueString())
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("timeout"),
+			"Invalid Timeout",
+			fmt.Sprintf("Could not parse %q as a duration: %s", value.ValueString(), err.Error()),
+		)
+	} else if timeout <= 0 {
+		diags.AddAttributeError(
+			path.Root("timeout"),
+			"Invalid Timeout",
+			fmt.Sprintf("The timeout must be positive, got %s", value.ValueString()),
+		)
+	}
+	return timeout, diags
+}
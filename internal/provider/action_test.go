@@ -0,0 +1,93 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// actionConfig builds the config of the action from the given attribute
+// values, leaving the others null.
+func actionConfig(t *testing.T, a action.Action, config map[string]any) tfsdk.Config {
+	t.Helper()
+	ctx := context.Background()
+
+	schemaResp := &action.SchemaResponse{}
+	a.Schema(ctx, action.SchemaRequest{}, schemaResp)
+	objectType, ok := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	if !ok {
+		t.Fatal("action schema type is not an object")
+	}
+	attrs := map[string]tftypes.Value{}
+	for name, typ := range objectType.AttributeTypes {
+		attrs[name] = tftypes.NewValue(typ, config[name])
+	}
+
+	return tfsdk.Config{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(objectType, attrs),
+	}
+}
+
+// invokeAction invokes the action with the given config and returns the
+// progress messages it sent along with the diagnostics.
+func invokeAction(t *testing.T, a action.Action, config map[string]any) ([]string, diag.Diagnostics) {
+	t.Helper()
+
+	var messages []string
+	resp := &action.InvokeResponse{
+		SendProgress: func(event action.InvokeProgressEvent) {
+			messages = append(messages, event.Message)
+		},
+	}
+	a.Invoke(context.Background(), action.InvokeRequest{Config: actionConfig(t, a, config)}, resp)
+	return messages, resp.Diagnostics
+}
+
+func TestReportProgress(t *testing.T) {
+	// Without a progress function, e.g. in a resource, nothing happens
+	reportProgress(context.Background(), "ignored %d", 1)
+
+	var events []action.InvokeProgressEvent
+	ctx := withProgress(context.Background(), func(event action.InvokeProgressEvent) {
+		events = append(events, event)
+	})
+	reportProgress(ctx, "attempt %d", 2)
+
+	if len(events) != 1 || events[0].Message != "attempt 2" {
+		t.Errorf("events = %v, want one with message %q", events, "attempt 2")
+	}
+}
+
+func TestParseActionTimeout(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     types.String
+		expected  time.Duration
+		expectErr bool
+	}{
+		{name: "null", value: types.StringNull(), expected: 10 * time.Minute},
+		{name: "unknown", value: types.StringUnknown(), expected: 10 * time.Minute},
+		{name: "valid", value: types.StringValue("90s"), expected: 90 * time.Second},
+		{name: "invalid", value: types.StringValue("soon"), expectErr: true},
+		{name: "zero", value: types.StringValue("0s"), expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timeout, diags := parseActionTimeout(tt.value, 10*time.Minute)
+			if diags.HasError() != tt.expectErr {
+				t.Fatalf("parseActionTimeout() diagnostics = %v, want error: %t", diags, tt.expectErr)
+			}
+			if !tt.expectErr && timeout != tt.expected {
+				t.Errorf("timeout = %s, want %s", timeout, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"terraform-provider-deno/client"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// apiLogSubsystem is the tflog subsystem of the API calls. Its level can
+	// be set separately with the TF_LOG_PROVIDER_DENO_API environment
+	// variable.
+	apiLogSubsystem = "deno_api"
+
+	// maxLoggedContentSize is the size above which the content of assets is
+	// replaced with its size in the logged bodies.
+	maxLoggedContentSize = 1024
+
+	// maxLoggedBodySize is the size above which the logged bodies are
+	// truncated.
+	maxLoggedBodySize = 64 * 1024
+
+	redacted = "<redacted>"
+)
+
+// privateKeyPattern matches PEM-encoded private keys of any type.
+var privateKeyPattern = regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`)
+
+// loggingDoer is an HTTP request doer that logs the requests to the Deno
+// Deploy API and their responses through the deno_api subsystem. The method,
+// URL, status, latency and trace ID are logged at DEBUG, and the headers and
+// bodies at TRACE, with the secrets redacted.
+type loggingDoer struct {
+	doer client.HttpRequestDoer
+	// trace reports whether the deno_api subsystem logs at TRACE. The bodies
+	// are only read and redacted then, as the uploads of the assets can be
+	// large.
+	trace bool
+}
+
+// newLoggingDoer returns a logging doer sending the requests with the doer.
+func newLoggingDoer(doer client.HttpRequestDoer) *loggingDoer {
+	return &loggingDoer{doer: doer, trace: apiTraceEnabled()}
+}
+
+// apiTraceEnabled reports whether the deno_api subsystem logs at TRACE. Its
+// level is taken from TF_LOG_PROVIDER_DENO_API, or else from the level of the
+// provider, the same way tflog does.
+func apiTraceEnabled() bool {
+	for _, name := range []string{"TF_LOG_PROVIDER_DENO_API", "TF_LOG_PROVIDER", "TF_LOG"} {
+		if level := strings.ToUpper(os.Getenv(name)); level != "" {
+			return level == "TRACE" || level == "JSON"
+		}
+	}
+	return false
+}
+
+// Do sends the request and logs it along with the response.
+func (d *loggingDoer) Do(req *http.Request) (*http.Response, error) {
+	ctx := apiLogContext(req)
+
+	if d.trace {
+		var requestBody []byte
+		if req.GetBody != nil {
+			if body, err := req.GetBody(); err == nil {
+				requestBody, _ = io.ReadAll(body)
+				body.Close()
+			}
+		}
+		tflog.SubsystemTrace(ctx, apiLogSubsystem, "Sending API request", map[string]any{
+			"headers": redactHeaders(req.Header),
+			"body":    redactBody(requestBody),
+		})
+	}
+
+	start := time.Now()
+	resp, err := d.doer.Do(req)
+	latency := time.Since(start)
+	if err != nil {
+		tflog.SubsystemDebug(ctx, apiLogSubsystem, "API request failed", map[string]any{
+			"latency": latency.String(),
+			"error":   err.Error(),
+		})
+		return nil, err
+	}
+
+	tflog.SubsystemDebug(ctx, apiLogSubsystem, "API request completed", map[string]any{
+		"status":     resp.StatusCode,
+		"latency":    latency.String(),
+		"x_deno_ray": resp.Header.Get(client.X_DENO_RAY),
+	})
+
+	if !d.trace {
+		return resp, nil
+	}
+
+	// The whole body is read by the client anyway, so it's buffered here to
+	// be logged and then read again.
+	responseBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(responseBody))
+	tflog.SubsystemTrace(ctx, apiLogSubsystem, "Received API response", map[string]any{
+		"status":  resp.StatusCode,
+		"headers": redactHeaders(resp.Header),
+		"body":    redactBody(responseBody),
+	})
+
+	return resp, nil
+}
+
+// apiLogContext returns the context of the request with the deno_api
+// subsystem logger, which logs the method and URL of the request.
+func apiLogContext(req *http.Request) context.Context {
+	ctx := tflog.NewSubsystem(req.Context(), apiLogSubsystem, tflog.WithLevelFromEnv("TF_LOG_PROVIDER_DENO_API"))
+	ctx = tflog.SubsystemSetField(ctx, apiLogSubsystem, "method", req.Method)
+	return tflog.SubsystemSetField(ctx, apiLogSubsystem, "url", req.URL.String())
+}
+
+// redactHeaders returns the headers with the credentials redacted.
+func redactHeaders(header http.Header) map[string]string {
+	redactedHeader := make(map[string]string, len(header))
+	for name, values := range header {
+		switch http.CanonicalHeaderKey(name) {
+		case "Authorization", "Cookie", "Set-Cookie", "Proxy-Authorization":
+			redactedHeader[name] = redacted
+		default:
+			redactedHeader[name] = fmt.Sprint(values)
+		}
+	}
+	return redactedHeader
+}
+
+// redactBody returns the body to log with the values of environment
+// variables, private keys and large asset contents redacted.
+func redactBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var value any
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err == nil {
+		var b bytes.Buffer
+		encoder := json.NewEncoder(&b)
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(redactJSON("", value)); err == nil {
+			body = bytes.TrimSuffix(b.Bytes(), []byte("\n"))
+		}
+	}
+
+	s := privateKeyPattern.ReplaceAllString(string(body), redacted)
+	if len(s) > maxLoggedBodySize {
+		s = fmt.Sprintf("%s... (%d bytes truncated)", s[:maxLoggedBodySize], len(s)-maxLoggedBodySize)
+	}
+	return s
+}
+
+// redactJSON redacts the secrets in the decoded JSON value, which is the
+// value of the given key in its parent object.
+func redactJSON(key string, value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		if key == "envVars" {
+			for name := range v {
+				v[name] = redacted
+			}
+			return v
+		}
+		for k, child := range v {
+			v[k] = redactJSON(k, child)
+		}
+		return v
+	case []any:
+		for i, child := range v {
+			v[i] = redactJSON(key, child)
+		}
+		return v
+	case string:
+		switch {
+		case key == "privateKey":
+			return redacted
+		case key == "content" && len(v) > maxLoggedContentSize:
+			return fmt.Sprintf("<%d bytes>", len(v))
+		}
+		return v
+	default:
+		return v
+	}
+}
@@ -0,0 +1,230 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// maxChangesetEntriesInSummary is the number of paths listed per category in
+// the plan warning. The full lists are always available in the `changeset`
+// attribute.
+const maxChangesetEntriesInSummary = 20
+
+var changesetAttrTypes = map[string]attr.Type{
+	"added":        types.ListType{ElemType: types.StringType},
+	"modified":     types.ListType{ElemType: types.StringType},
+	"removed":      types.ListType{ElemType: types.StringType},
+	"upload_bytes": types.Int64Type,
+	"summary":      types.StringType,
+}
+
+// assetChangeset describes the file-level difference between two sets of
+// deployment assets.
+type assetChangeset struct {
+	Added    []string
+	Modified []string
+	Removed  []string
+	// UploadBytes is the total size of the added and modified files.
+	UploadBytes int64
+}
+
+// assetDigest is the comparable identity of a single asset.
+type assetDigest struct {
+	kind    string
+	gitSHA1 string
+	target  string
+	size    int64
+}
+
+// digestAsset computes the digest of the given asset. The returned bool is
+// false when the digest cannot be determined at plan time, e.g. because some
+// of the attributes are still unknown. An error is returned when the content
+// of the asset can't be read.
+func digestAsset(a asset) (assetDigest, bool, error) {
+	if a.Kind.IsUnknown() || a.GitSHA1.IsUnknown() || a.Content.IsUnknown() || a.Encoding.IsUnknown() || a.LocalFilePath.IsUnknown() || a.RuntimeTargetPath.IsUnknown() {
+		return assetDigest{}, false, nil
+	}
+
+	d := assetDigest{kind: a.Kind.ValueString()}
+	switch d.kind {
+	case "symlink":
+		d.target = a.RuntimeTargetPath.ValueString()
+	case "file":
+		if !a.Content.IsNull() {
+			b := []byte(a.Content.ValueString())
+			if a.Encoding.ValueString() == "base64" {
+				decoded, err := base64.StdEncoding.DecodeString(a.Content.ValueString())
+				if err != nil {
+					return assetDigest{}, false, fmt.Errorf("could not decode the base64 content: %w", err)
+				}
+				b = decoded
+			}
+			d.gitSHA1 = calculateGitSha1(b)
+			d.size = int64(len(b))
+		} else if !a.LocalFilePath.IsNull() {
+			if !a.GitSHA1.IsNull() {
+				d.gitSHA1 = a.GitSHA1.ValueString()
+				if stat, err := os.Stat(a.LocalFilePath.ValueString()); err == nil {
+					d.size = stat.Size()
+				}
+			} else {
+				b, err := os.ReadFile(a.LocalFilePath.ValueString())
+				if err != nil {
+					return assetDigest{}, false, fmt.Errorf("could not read the content: %w", err)
+				}
+				d.gitSHA1 = calculateGitSha1(b)
+				d.size = int64(len(b))
+			}
+		}
+	}
+
+	return d, true, nil
+}
+
+// newAssetChangeset returns a changeset without any change.
+func newAssetChangeset() assetChangeset {
+	return assetChangeset{
+		Added:    []string{},
+		Modified: []string{},
+		Removed:  []string{},
+	}
+}
+
+// diffAssets compares the assets in the prior state with the planned ones per
+// runtime path. The returned bool is false when the difference cannot be
+// determined at plan time. A planned asset that can't be read is reported as
+// an error, while a prior one is taken as modified, as its local file may
+// have been moved since.
+func diffAssets(oldAssets, newAssets map[string]asset) (assetChangeset, bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	changeset := newAssetChangeset()
+
+	for runtimePath, na := range newAssets {
+		newDigest, ok, err := digestAsset(na)
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("assets").AtMapKey(runtimePath),
+				"Unable to Read Asset",
+				fmt.Sprintf("Could not compute the digest of %s: %s", runtimePath, err.Error()),
+			)
+			continue
+		}
+		if !ok {
+			return assetChangeset{}, false, diags
+		}
+
+		oa, exists := oldAssets[runtimePath]
+		if !exists {
+			changeset.Added = append(changeset.Added, runtimePath)
+			changeset.UploadBytes += newDigest.size
+			continue
+		}
+
+		oldDigest, ok, err := digestAsset(oa)
+		if err == nil && !ok {
+			return assetChangeset{}, false, diags
+		}
+		if err != nil || oldDigest.kind != newDigest.kind || oldDigest.gitSHA1 != newDigest.gitSHA1 || oldDigest.target != newDigest.target {
+			changeset.Modified = append(changeset.Modified, runtimePath)
+			changeset.UploadBytes += newDigest.size
+		}
+	}
+	if diags.HasError() {
+		return assetChangeset{}, false, diags
+	}
+
+	for runtimePath := range oldAssets {
+		if _, exists := newAssets[runtimePath]; !exists {
+			changeset.Removed = append(changeset.Removed, runtimePath)
+		}
+	}
+
+	sort.Strings(changeset.Added)
+	sort.Strings(changeset.Modified)
+	sort.Strings(changeset.Removed)
+
+	return changeset, true, diags
+}
+
+// assetsSemanticallyEqual reports whether the two sets of assets would produce
+// the same deployment. Two assets compare equal when their runtime path, kind,
+// symlink target and git SHA1 match; the local `content_source_path` does not
+// matter.
+func assetsSemanticallyEqual(oldAssets, newAssets map[string]asset) (bool, diag.Diagnostics) {
+	changeset, ok, diags := diffAssets(oldAssets, newAssets)
+	return ok && changeset.IsEmpty(), diags
+}
+
+// IsEmpty returns true if no asset is added, modified or removed.
+func (c assetChangeset) IsEmpty() bool {
+	return len(c.Added) == 0 && len(c.Modified) == 0 && len(c.Removed) == 0
+}
+
+// Summary renders the changeset in a concise, human-readable form.
+func (c assetChangeset) Summary() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d added, %d modified, %d removed (%s to upload)\n", len(c.Added), len(c.Modified), len(c.Removed), formatBytes(c.UploadBytes))
+
+	writeSection := func(marker string, paths []string) {
+		for i, p := range paths {
+			if i == maxChangesetEntriesInSummary {
+				fmt.Fprintf(&sb, "  ... and %d more\n", len(paths)-maxChangesetEntriesInSummary)
+				break
+			}
+			fmt.Fprintf(&sb, "  %s %s\n", marker, p)
+		}
+	}
+	writeSection("+", c.Added)
+	writeSection("~", c.Modified)
+	writeSection("-", c.Removed)
+
+	return sb.String()
+}
+
+// ToObject converts the changeset to the `changeset` attribute value.
+func (c assetChangeset) ToObject(ctx context.Context) (types.Object, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	added, d := types.ListValueFrom(ctx, types.StringType, c.Added)
+	diags.Append(d...)
+	modified, d := types.ListValueFrom(ctx, types.StringType, c.Modified)
+	diags.Append(d...)
+	removed, d := types.ListValueFrom(ctx, types.StringType, c.Removed)
+	diags.Append(d...)
+	if diags.HasError() {
+		return types.ObjectNull(changesetAttrTypes), diags
+	}
+
+	obj, d := types.ObjectValue(changesetAttrTypes, map[string]attr.Value{
+		"added":        added,
+		"modified":     modified,
+		"removed":      removed,
+		"upload_bytes": types.Int64Value(c.UploadBytes),
+		"summary":      types.StringValue(c.Summary()),
+	})
+	diags.Append(d...)
+
+	return obj, diags
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,255 @@
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func fileAsset(gitSHA1 string) asset {
+	return asset{
+		Kind:              types.StringValue("file"),
+		LocalFilePath:     types.StringValue("/nonexistent/" + gitSHA1),
+		RuntimeTargetPath: types.StringNull(),
+		GitSHA1:           types.StringValue(gitSHA1),
+		Content:           types.StringNull(),
+		Encoding:          types.StringNull(),
+	}
+}
+
+func symlinkAsset(target string) asset {
+	return asset{
+		Kind:              types.StringValue("symlink"),
+		LocalFilePath:     types.StringNull(),
+		RuntimeTargetPath: types.StringValue(target),
+		GitSHA1:           types.StringNull(),
+		Content:           types.StringNull(),
+		Encoding:          types.StringNull(),
+	}
+}
+
+func inlineAsset(content string, encoding string) asset {
+	enc := types.StringNull()
+	if encoding != "" {
+		enc = types.StringValue(encoding)
+	}
+	return asset{
+		Kind:              types.StringValue("file"),
+		LocalFilePath:     types.StringNull(),
+		RuntimeTargetPath: types.StringNull(),
+		GitSHA1:           types.StringNull(),
+		Content:           types.StringValue(content),
+		Encoding:          enc,
+	}
+}
+
+func TestDiffAssets(t *testing.T) {
+	tests := []struct {
+		name      string
+		oldAssets map[string]asset
+		newAssets map[string]asset
+		expected  assetChangeset
+	}{
+		{
+			name:      "create",
+			oldAssets: nil,
+			newAssets: map[string]asset{
+				"main.ts": inlineAsset("hey", ""),
+			},
+			expected: assetChangeset{
+				Added:       []string{"main.ts"},
+				Modified:    []string{},
+				Removed:     []string{},
+				UploadBytes: 3,
+			},
+		},
+		{
+			name: "no change",
+			oldAssets: map[string]asset{
+				"main.ts": fileAsset("aaa"),
+				"link.ts": symlinkAsset("main.ts"),
+			},
+			newAssets: map[string]asset{
+				"main.ts": fileAsset("aaa"),
+				"link.ts": symlinkAsset("main.ts"),
+			},
+			expected: assetChangeset{
+				Added:    []string{},
+				Modified: []string{},
+				Removed:  []string{},
+			},
+		},
+		{
+			name: "added, modified and removed",
+			oldAssets: map[string]asset{
+				"main.ts":   fileAsset("aaa"),
+				"util.ts":   fileAsset("bbb"),
+				"link.ts":   symlinkAsset("main.ts"),
+				"inline.ts": inlineAsset("hey", ""),
+			},
+			newAssets: map[string]asset{
+				"main.ts":   fileAsset("ccc"),
+				"new.ts":    fileAsset("ddd"),
+				"link.ts":   symlinkAsset("new.ts"),
+				"inline.ts": inlineAsset("aGV5", "base64"),
+			},
+			expected: assetChangeset{
+				Added:    []string{"new.ts"},
+				Modified: []string{"link.ts", "main.ts"},
+				Removed:  []string{"util.ts"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, diags := diffAssets(tt.oldAssets, tt.newAssets)
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+			if !ok {
+				t.Fatal("diffAssets() reported unknown changeset")
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("diffAssets() = %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDiffAssets_Unknown(t *testing.T) {
+	a := fileAsset("aaa")
+	a.GitSHA1 = types.StringUnknown()
+
+	_, ok, _ := diffAssets(nil, map[string]asset{"main.ts": a})
+	if ok {
+		t.Error("diffAssets() should report unknown changeset when git_sha1 is unknown")
+	}
+}
+
+func TestDiffAssets_UploadBytesFromLocalFile(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "main.ts")
+	if err := os.WriteFile(p, []byte("console.log(42)"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	a := fileAsset("")
+	a.LocalFilePath = types.StringValue(p)
+	a.GitSHA1 = types.StringNull()
+
+	got, ok, _ := diffAssets(nil, map[string]asset{"main.ts": a})
+	if !ok {
+		t.Fatal("diffAssets() reported unknown changeset")
+	}
+	if got.UploadBytes != 15 {
+		t.Errorf("UploadBytes = %d, want 15", got.UploadBytes)
+	}
+}
+
+func TestDiffAssets_Unreadable(t *testing.T) {
+	missing := fileAsset("")
+	missing.LocalFilePath = types.StringValue(filepath.Join(t.TempDir(), "missing.ts"))
+	missing.GitSHA1 = types.StringNull()
+
+	// A planned asset that can't be read is an error
+	_, ok, diags := diffAssets(map[string]asset{"main.ts": missing}, map[string]asset{"main.ts": missing})
+	if ok || !diags.HasError() {
+		t.Errorf("diffAssets() = %t, %v, want an error", ok, diags)
+	}
+	if equal, diags := assetsSemanticallyEqual(map[string]asset{"main.ts": missing}, map[string]asset{"main.ts": missing}); equal || !diags.HasError() {
+		t.Errorf("assetsSemanticallyEqual() = %t, %v, want an error", equal, diags)
+	}
+
+	// A prior asset that can't be read is modified
+	got, ok, diags := diffAssets(map[string]asset{"main.ts": missing}, map[string]asset{"main.ts": fileAsset("aaa")})
+	if !ok || diags.HasError() {
+		t.Fatalf("diffAssets() = %t, %v", ok, diags)
+	}
+	if !reflect.DeepEqual(got.Modified, []string{"main.ts"}) {
+		t.Errorf("Modified = %q, want main.ts", got.Modified)
+	}
+}
+
+func TestAssetChangesetSummary(t *testing.T) {
+	added := make([]string, maxChangesetEntriesInSummary+5)
+	for i := range added {
+		added[i] = "file.ts"
+	}
+	cs := assetChangeset{
+		Added:       added,
+		Modified:    []string{"main.ts"},
+		Removed:     []string{"old.ts"},
+		UploadBytes: 2048,
+	}
+
+	summary := cs.Summary()
+	if !strings.HasPrefix(summary, "25 added, 1 modified, 1 removed (2.0 KiB to upload)\n") {
+		t.Errorf("unexpected summary header: %s", summary)
+	}
+	if !strings.Contains(summary, "  ... and 5 more\n") {
+		t.Errorf("summary should truncate long lists: %s", summary)
+	}
+	if !strings.Contains(summary, "  ~ main.ts\n") || !strings.Contains(summary, "  - old.ts\n") {
+		t.Errorf("summary should list modified and removed files: %s", summary)
+	}
+}
+
+func TestAssetsSemanticallyEqual(t *testing.T) {
+	moved := fileAsset("aaa")
+	moved.LocalFilePath = types.StringValue("/moved/main.ts")
+
+	tests := []struct {
+		name      string
+		oldAssets map[string]asset
+		newAssets map[string]asset
+		expected  bool
+	}{
+		{
+			name:      "only content_source_path changes",
+			oldAssets: map[string]asset{"main.ts": fileAsset("aaa")},
+			newAssets: map[string]asset{"main.ts": moved},
+			expected:  true,
+		},
+		{
+			name:      "git_sha1 changes",
+			oldAssets: map[string]asset{"main.ts": fileAsset("aaa")},
+			newAssets: map[string]asset{"main.ts": fileAsset("bbb")},
+			expected:  false,
+		},
+		{
+			name:      "runtime path changes",
+			oldAssets: map[string]asset{"main.ts": fileAsset("aaa")},
+			newAssets: map[string]asset{"src/main.ts": fileAsset("aaa")},
+			expected:  false,
+		},
+		{
+			name:      "symlink target changes",
+			oldAssets: map[string]asset{"link.ts": symlinkAsset("a.ts")},
+			newAssets: map[string]asset{"link.ts": symlinkAsset("b.ts")},
+			expected:  false,
+		},
+		{
+			name:      "inline content with different encoding",
+			oldAssets: map[string]asset{"main.ts": inlineAsset("hey", "utf-8")},
+			newAssets: map[string]asset{"main.ts": inlineAsset("aGV5", "base64")},
+			expected:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, diags := assetsSemanticallyEqual(tt.oldAssets, tt.newAssets)
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+			if got != tt.expected {
+				t.Errorf("assetsSemanticallyEqual() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-deno/client"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ list.ListResource              = &deploymentListResource{}
+	_ list.ListResourceWithConfigure = &deploymentListResource{}
+)
+
+// NewDeploymentListResource is a helper function to simplify the provider implementation.
+func NewDeploymentListResource() list.ListResource {
+	return &deploymentListResource{}
+}
+
+// listedDeploymentEntryPoint is the placeholder `entry_point_url` of the
+// listed deployments, as the API doesn't return it.
+const listedDeploymentEntryPoint = "main.ts"
+
+// deploymentListResource lists the deployments of a project.
+type deploymentListResource struct {
+	client         client.ClientWithResponsesInterface
+	organizationID uuid.UUID
+}
+
+// deploymentListResourceModel maps the list resource schema data.
+type deploymentListResourceModel struct {
+	ProjectID types.String `tfsdk:"project_id"`
+	Status    types.String `tfsdk:"status"`
+}
+
+// Metadata returns the resource type name.
+func (r *deploymentListResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_deployment"
+}
+
+// ListResourceConfigSchema defines the filters of the list resource.
+func (r *deploymentListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: `
+Lists the deployments of a project.
+
+The API doesn't return the entry point, assets and environment variables of a deployment, so the configuration generated for the listed deployments has an empty ` + "`assets`" + ` and ` + "`env_vars`" + `, and ` + "`" + listedDeploymentEntryPoint + "`" + ` as a placeholder ` + "`entry_point_url`" + `. They have to be filled in before the deployment can be imported.
+		`,
+		Attributes: map[string]schema.Attribute{
+			"project_id": schema.StringAttribute{
+				Required:    true,
+				Description: "The ID of the project whose deployments are listed.",
+			},
+			"status": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only list the deployments with this status: `pending`, `success` or `failed`.",
+			},
+		},
+	}
+}
+
+// List streams the deployments matching the filters.
+func (r *deploymentListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var config deploymentListResourceModel
+	diags := req.Config.Get(ctx, &config)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	projectID, err := uuid.Parse(config.ProjectID.ValueString())
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("project_id"),
+			"Invalid Project ID",
+			fmt.Sprintf("Could not parse project ID %s: %s", config.ProjectID, err.Error()),
+		)
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	fetchPage := func(ctx context.Context, page, limit int) ([]client.Deployment, error) {
+		res, err := r.client.ListDeploymentsWithResponse(ctx, projectID, &client.ListDeploymentsParams{
+			Page:  &page,
+			Limit: &limit,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if client.RespIsError(res) {
+			return nil, fmt.Errorf("%s", client.APIErrorDetail(res.HTTPResponse, res.Body))
+		}
+		if res.JSON200 == nil {
+			return nil, nil
+		}
+		return *res.JSON200, nil
+	}
+
+	stream.Results = func(push func(list.ListResult) bool) {
+		var count int64
+		for deployment, err := range listPages(ctx, fetchPage) {
+			if err != nil {
+				var diags diag.Diagnostics
+				diags.AddError(fmt.Sprintf("Unable to List Deployments of Project %s", projectID), err.Error())
+				push(list.ListResult{Diagnostics: diags})
+				return
+			}
+			if !config.Status.IsNull() && string(deployment.Status) != config.Status.ValueString() {
+				continue
+			}
+
+			result := req.NewListResult(ctx)
+			result.DisplayName = deployment.Id
+			result.Diagnostics.Append(result.Identity.Set(ctx, newDeploymentResourceIdentity(r.organizationID, types.StringValue(deployment.Id)))...)
+			if req.IncludeResource {
+				result.Diagnostics.Append(setListedDeployment(ctx, result.Resource, deployment)...)
+			}
+
+			if !push(result) {
+				return
+			}
+			count++
+			if req.Limit > 0 && count >= req.Limit {
+				return
+			}
+		}
+	}
+}
+
+// setListedDeployment sets the attributes of the deployment that the API
+// returns. The assets are left empty and the other attributes null.
+func setListedDeployment(ctx context.Context, state *tfsdk.Resource, deployment client.Deployment) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	domains := []string{}
+	if deployment.Domains != nil {
+		domains = *deployment.Domains
+	}
+
+	diags.Append(state.SetAttribute(ctx, path.Root("deployment_id"), deployment.Id)...)
+	diags.Append(state.SetAttribute(ctx, path.Root("project_id"), deployment.ProjectId.String())...)
+	diags.Append(state.SetAttribute(ctx, path.Root("status"), string(deployment.Status))...)
+	diags.Append(state.SetAttribute(ctx, path.Root("domains"), domains)...)
+	diags.Append(state.SetAttribute(ctx, path.Root("entry_point_url"), listedDeploymentEntryPoint)...)
+	diags.Append(state.SetAttribute(ctx, path.Root("assets"), map[string]asset{})...)
+	diags.Append(state.SetAttribute(ctx, path.Root("env_vars"), map[string]string{})...)
+	diags.Append(state.SetAttribute(ctx, path.Root("uploaded_assets"), types.MapValueMust(types.ObjectType{AttrTypes: uploadedAssetAttrTypes}, nil))...)
+	diags.Append(state.SetAttribute(ctx, path.Root("created_at"), deployment.CreatedAt.Format(time.RFC3339))...)
+	diags.Append(state.SetAttribute(ctx, path.Root("updated_at"), deployment.UpdatedAt.Format(time.RFC3339))...)
+	return diags
+}
+
+// Configure adds the provider configured client to the list resource.
+func (r *deploymentListResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*deployProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected List Resource Configure Type",
+			fmt.Sprintf("Expected *deployProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	resp.Diagnostics.Append(providerData.connect()...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.client = providerData.client
+	r.organizationID = providerData.organizationID
+}
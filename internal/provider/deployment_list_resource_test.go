@@ -0,0 +1,104 @@
+package provider
+
+import (
+	"context"
+	"terraform-provider-deno/client"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestDeploymentListResource(t *testing.T) {
+	organizationID := uuid.New()
+	projectID := uuid.New()
+	fake := &fakeListClient{
+		deployments: []client.Deployment{
+			{
+				Id:        "abcdefghijkl",
+				ProjectId: projectID,
+				Status:    client.DeploymentStatusSuccess,
+				Domains:   &[]string{"my-project-abcdefghijkl.deno.dev"},
+				CreatedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+				UpdatedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+			{Id: "failedfailed", ProjectId: projectID, Status: client.DeploymentStatusFailed},
+			{Id: "otherproject", ProjectId: uuid.New(), Status: client.DeploymentStatusSuccess},
+		},
+	}
+	lr := &deploymentListResource{client: fake, organizationID: organizationID}
+
+	results, diags := runList(t, lr, &deploymentResource{}, map[string]any{"project_id": projectID.String()}, false, 0)
+	if diags.HasError() {
+		t.Fatalf("List() returned unexpected diagnostics: %v", diags)
+	}
+	if len(results) != 2 {
+		t.Fatalf("List() returned %d results, want 2", len(results))
+	}
+
+	results, diags = runList(t, lr, &deploymentResource{}, map[string]any{"project_id": projectID.String(), "status": "success"}, true, 0)
+	if diags.HasError() {
+		t.Fatalf("List() returned unexpected diagnostics: %v", diags)
+	}
+	if len(results) != 1 {
+		t.Fatalf("List() returned %d results, want 1", len(results))
+	}
+
+	var identity deploymentResourceIdentityModel
+	if diags := results[0].Identity.Get(context.Background(), &identity); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if identity.OrganizationID.ValueString() != organizationID.String() || identity.DeploymentID.ValueString() != "abcdefghijkl" {
+		t.Errorf("identity = %v", identity)
+	}
+
+	ctx := context.Background()
+	var projectIDValue, status types.String
+	var domains types.Set
+	var assets types.Map
+	results[0].Resource.GetAttribute(ctx, path.Root("project_id"), &projectIDValue)
+	results[0].Resource.GetAttribute(ctx, path.Root("status"), &status)
+	results[0].Resource.GetAttribute(ctx, path.Root("domains"), &domains)
+	results[0].Resource.GetAttribute(ctx, path.Root("assets"), &assets)
+	if projectIDValue.ValueString() != projectID.String() || status.ValueString() != "success" {
+		t.Errorf("project_id = %s, status = %s", projectIDValue, status)
+	}
+	if len(domains.Elements()) != 1 {
+		t.Errorf("domains = %s, want 1 domain", domains)
+	}
+	if assets.IsNull() || len(assets.Elements()) != 0 {
+		t.Errorf("assets = %s, want an empty map", assets)
+	}
+
+	// The generated configuration sets all the required attributes
+	schemaResp := &resource.SchemaResponse{}
+	(&deploymentResource{}).Schema(ctx, resource.SchemaRequest{}, schemaResp)
+	for name, attribute := range schemaResp.Schema.Attributes {
+		if !attribute.IsRequired() {
+			continue
+		}
+		var value attr.Value
+		if diags := results[0].Resource.GetAttribute(ctx, path.Root(name), &value); diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		if value.IsNull() || value.IsUnknown() {
+			t.Errorf("required attribute %s is not set", name)
+		}
+	}
+}
+
+func TestDeploymentListResource_InvalidProjectID(t *testing.T) {
+	lr := &deploymentListResource{client: &fakeListClient{}, organizationID: uuid.New()}
+
+	results, diags := runList(t, lr, &deploymentResource{}, map[string]any{"project_id": "not-a-uuid"}, false, 0)
+	if !diags.HasError() {
+		t.Fatal("List() should fail for an invalid project ID")
+	}
+	if len(results) != 0 {
+		t.Errorf("List() returned %d results, want none", len(results))
+	}
+}
@@ -15,16 +15,26 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource              = &deploymentResource{}
-	_ resource.ResourceWithConfigure = &deploymentResource{}
+	_ resource.Resource                   = &deploymentResource{}
+	_ resource.ResourceWithConfigure      = &deploymentResource{}
+	_ resource.ResourceWithModifyPlan     = &deploymentResource{}
+	_ resource.ResourceWithValidateConfig = &deploymentResource{}
+	_ resource.ResourceWithIdentity       = &deploymentResource{}
+	_ resource.ResourceWithImportState    = &deploymentResource{}
+	_ resource.ResourceWithUpgradeState   = &deploymentResource{}
 )
 
 // NewDeploymentResource is a helper function to simplify the provider implementation.
@@ -51,9 +61,16 @@ type deploymentResourceModel struct {
 	Assets          map[string]asset      `tfsdk:"assets"`
 	UploadedAssets  types.Map             `tfsdk:"uploaded_assets"`
 	EnvVars         types.Map             `tfsdk:"env_vars"`
-	CreatedAt       types.String          `tfsdk:"created_at"`
-	UpdatedAt       types.String          `tfsdk:"updated_at"`
-	Timeouts        timeouts.Value        `tfsdk:"timeouts"`
+	SecretEnvVars   types.Map             `tfsdk:"secret_env_vars"`
+	// SecretEnvVarsWO is write-only; it's always null in plan and state, and
+	// the configured value has to be read from the config.
+	SecretEnvVarsWO     types.Map         `tfsdk:"secret_env_vars_wo"`
+	SecretEnvVarsWOHash types.String      `tfsdk:"secret_env_vars_wo_hash"`
+	Changeset           types.Object      `tfsdk:"changeset"`
+	HealthCheck         *healthCheckModel `tfsdk:"health_check"`
+	CreatedAt           types.String      `tfsdk:"created_at"`
+	UpdatedAt           types.String      `tfsdk:"updated_at"`
+	Timeouts            timeouts.Value    `tfsdk:"timeouts"`
 }
 
 // compilerOptionsModel maps the compiler options schema data.
@@ -64,6 +81,21 @@ type compilerOptionsModel struct {
 	JSXImportSource    types.String `tfsdk:"jsx_import_source"`
 }
 
+var uploadedAssetAttrTypes = map[string]attr.Type{
+	"path":       types.StringType,
+	"git_sha1":   types.StringType,
+	"updated_at": types.StringType,
+}
+
+var assetAttrTypes = map[string]attr.Type{
+	"kind":                types.StringType,
+	"content_source_path": types.StringType,
+	"target":              types.StringType,
+	"git_sha1":            types.StringType,
+	"content":             types.StringType,
+	"encoding":            types.StringType,
+}
+
 type asset struct {
 	Kind              types.String `tfsdk:"kind"`
 	LocalFilePath     types.String `tfsdk:"content_source_path"`
@@ -76,15 +108,50 @@ type asset struct {
 // Metadata returns the resource type name.
 func (r *deploymentResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_deployment"
+	// Deployments are immutable, so an update that changes the content
+	// creates a new deployment with a new ID.
+	resp.ResourceBehavior.MutableIdentity = true
+}
+
+// deploymentResourceIdentityModel maps the resource identity schema data.
+type deploymentResourceIdentityModel struct {
+	OrganizationID types.String `tfsdk:"organization_id"`
+	DeploymentID   types.String `tfsdk:"deployment_id"`
+}
+
+func newDeploymentResourceIdentity(organizationID uuid.UUID, deploymentID types.String) deploymentResourceIdentityModel {
+	return deploymentResourceIdentityModel{
+		OrganizationID: types.StringValue(organizationID.String()),
+		DeploymentID:   deploymentID,
+	}
+}
+
+// IdentitySchema defines the identity of the resource.
+func (r *deploymentResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"organization_id": identityschema.StringAttribute{
+				OptionalForImport: true,
+				Description:       "The ID of the organization that the deployment belongs to. Defaults to the organization of the provider.",
+			},
+			"deployment_id": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The ID of the deployment.",
+			},
+		},
+	}
 }
 
 // Schema defines the schema for the resource.
 func (r *deploymentResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version: deploymentSchemaVersion,
 		Description: `
 A resource for a Deno Deploy deployment.
 
 A deployment belongs to a project, is an immutable, invokable snapshot of the project's assets, can be assigned a custom domain.
+
+Deployments can't be deleted. Destroying this resource detaches the custom domains that are associated with the deployment, so that they no longer route to it, and removes the deployment from the Terraform state. The deployment itself, including its deno.dev domains, stays available.
 		`,
 		Attributes: map[string]schema.Attribute{
 			"deployment_id": schema.StringAttribute{
@@ -145,7 +212,7 @@ A deployment belongs to a project, is an immutable, invokable snapshot of the pr
 						},
 						"content_source_path": schema.StringAttribute{
 							Optional:    true,
-							Description: "The file path of the asset in the local filesystem.",
+							Description: "The file path of the asset in the local filesystem. Changing only this attribute does not create a new deployment as long as the content stays the same.",
 						},
 						"target": schema.StringAttribute{
 							Optional:    true,
@@ -186,7 +253,94 @@ A deployment belongs to a project, is an immutable, invokable snapshot of the pr
 			"env_vars": schema.MapAttribute{
 				Required:    true,
 				ElementType: types.StringType,
-				Description: "The environment variables to be set in the runtime environment of the deployment.",
+				Description: "The environment variables to be set in the runtime environment of the deployment. Names must start with a letter or an underscore and contain only letters, digits and underscores, must be at most 128 bytes long, and must not start with `DENO_`, `LD_` or `OTEL_`, which are reserved by Deno Deploy, except for `DENO_AUTH_TOKENS`, `DENO_COMPAT`, `DENO_CONDITIONS`, `DENO_DEPLOY_ENDPOINT` and `DENO_DEPLOY_TOKEN`. Each value must be at most 16 KiB.",
+				Validators: []validator.Map{
+					envVarsValidator{},
+				},
+			},
+			"secret_env_vars": schema.MapAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				ElementType: types.StringType,
+				Description: "The environment variables holding secrets such as database URLs and API keys. They are merged with `env_vars` and hidden from the plan output, but still stored in the state. On Terraform 1.11 and later, prefer `secret_env_vars_wo`. A key must not be defined in more than one of `env_vars`, `secret_env_vars` and `secret_env_vars_wo`. The same rules as `env_vars` apply.",
+				Validators: []validator.Map{
+					envVarsValidator{},
+				},
+			},
+			"secret_env_vars_wo": schema.MapAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				WriteOnly:   true,
+				ElementType: types.StringType,
+				Description: "The write-only variant of `secret_env_vars`, which is never persisted to the plan or state. Changes are detected through `secret_env_vars_wo_hash`. This requires Terraform 1.11 or later.",
+				Validators: []validator.Map{
+					envVarsValidator{},
+				},
+			},
+			"secret_env_vars_wo_hash": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The salted HMAC-SHA256 digest of `secret_env_vars_wo`, used to trigger a new deployment when the write-only values change.",
+			},
+			"changeset": schema.SingleNestedAttribute{
+				Computed:    true,
+				Description: "The file-level difference of `assets` between the previous deployment and this one, computed at plan time by comparing the git SHA1 of each runtime path. This is intended to be posted to pull requests by CI to describe what a deploy actually changes.",
+				Attributes: map[string]schema.Attribute{
+					"added": schema.ListAttribute{
+						Computed:    true,
+						ElementType: types.StringType,
+						Description: "The runtime paths of the assets that are newly added.",
+					},
+					"modified": schema.ListAttribute{
+						Computed:    true,
+						ElementType: types.StringType,
+						Description: "The runtime paths of the assets whose content, kind or symlink target has changed.",
+					},
+					"removed": schema.ListAttribute{
+						Computed:    true,
+						ElementType: types.StringType,
+						Description: "The runtime paths of the assets that are removed.",
+					},
+					"upload_bytes": schema.Int64Attribute{
+						Computed:    true,
+						Description: "The total size in bytes of the added and modified files.",
+					},
+					"summary": schema.StringAttribute{
+						Computed:    true,
+						Description: "A human-readable summary of the changeset.",
+					},
+				},
+			},
+			"health_check": schema.SingleNestedAttribute{
+				Optional:    true,
+				Description: "HTTP health check performed against every domain of the deployment after it has succeeded. If any of the domains doesn't pass the check within the given retries, the apply fails with the last response attached.",
+				Attributes: map[string]schema.Attribute{
+					"path": schema.StringAttribute{
+						Optional:    true,
+						Description: "The path to request. Defaults to `/`.",
+					},
+					"expected_status": schema.Int64Attribute{
+						Optional:    true,
+						Description: "The expected HTTP status code of the response, between 100 and 599. Defaults to `200`.",
+					},
+					"body_regex": schema.StringAttribute{
+						Optional:    true,
+						Description: "A regular expression that the response body must match. Only the first 4096 bytes of the body are examined.",
+					},
+					"headers": schema.MapAttribute{
+						Optional:    true,
+						ElementType: types.StringType,
+						Description: "The HTTP headers to send with the request.",
+					},
+					"retries": schema.Int64Attribute{
+						Optional:    true,
+						Description: "The number of times the check is retried after a failed attempt. Defaults to `5`.",
+					},
+					"interval": schema.StringAttribute{
+						Optional:    true,
+						Description: "The time to wait between attempts, such as `10s`. It must be positive. Defaults to `5s`.",
+					},
+				},
 			},
 			"created_at": schema.StringAttribute{
 				Computed:            true,
@@ -205,8 +359,17 @@ A deployment belongs to a project, is an immutable, invokable snapshot of the pr
 	}
 }
 
-func prepareAssetsForUpload(plannedAssets map[string]asset) (client.Assets, diag.Diagnostic) {
-	assets := make(client.Assets)
+func prepareAssetsForUpload(ctx context.Context, plannedAssets map[string]asset) (assets client.Assets, result diag.Diagnostic) {
+	_, span := startSpan(ctx, "prepareAssetsForUpload", attribute.Int("assets", len(plannedAssets)))
+	defer func() {
+		var diags diag.Diagnostics
+		if result != nil {
+			diags.Append(result)
+		}
+		endSpan(span, &diags)
+	}()
+
+	assets = make(client.Assets)
 
 	for runtimePath, pa := range plannedAssets {
 		kind := pa.Kind.ValueString()
@@ -343,8 +506,128 @@ func prepareAssetsForUpload(plannedAssets map[string]asset) (client.Assets, diag
 	return assets, nil
 }
 
+// ModifyPlan computes the file-level changeset of the planned deployment and
+// reports it as a warning so that reviewers can tell what a deploy actually
+// changes.
+func (r *deploymentResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to do on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	// Write-only values are only available in the config; track their changes
+	// through the hash.
+	hashChanged, diags := planSecretEnvVarsWOHash(ctx, req, resp)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The changeset is marked unknown by the framework only when the resource
+	// is going to be created or updated. Otherwise keep the value in state.
+	var changeset types.Object
+	diags = req.Plan.GetAttribute(ctx, path.Root("changeset"), &changeset)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !changeset.IsUnknown() {
+		if hashChanged {
+			// Only the write-only values have changed, which the framework
+			// can't see; a new deployment will be created.
+			diags = markDeploymentComputedUnknown(ctx, resp)
+			resp.Diagnostics.Append(diags...)
+		}
+		return
+	}
+
+	var plannedAssetsValue types.Map
+	diags = req.Plan.GetAttribute(ctx, path.Root("assets"), &plannedAssetsValue)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || plannedAssetsValue.IsUnknown() {
+		return
+	}
+
+	var plannedAssets map[string]asset
+	diags = plannedAssetsValue.ElementsAs(ctx, &plannedAssets, false)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var priorAssets map[string]asset
+	if !req.State.Raw.IsNull() {
+		var state deploymentResourceModel
+		diags = req.State.Get(ctx, &state)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		priorAssets = state.Assets
+
+		redeploy, diags := requiresRedeploy(ctx, req.State, resp.Plan)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if !redeploy {
+			// Only attributes that don't affect the deployment have changed,
+			// e.g. the local `content_source_path` of assets. The update won't
+			// create a new deployment, so the computed values stay the same.
+			for p, v := range map[string]attr.Value{
+				"deployment_id":   state.DeploymentID,
+				"status":          state.Status,
+				"domains":         state.Domains,
+				"uploaded_assets": state.UploadedAssets,
+				"changeset":       state.Changeset,
+				"created_at":      state.CreatedAt,
+				"updated_at":      state.UpdatedAt,
+			} {
+				diags = resp.Plan.SetAttribute(ctx, path.Root(p), v)
+				resp.Diagnostics.Append(diags...)
+			}
+			return
+		}
+	}
+
+	cs, ok, diags := diffAssets(priorAssets, plannedAssets)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !ok {
+		// Some of the assets are not known until apply
+		return
+	}
+
+	changeset, diags = cs.ToObject(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.Plan.SetAttribute(ctx, path.Root("changeset"), changeset)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if cs.IsEmpty() {
+		return
+	}
+
+	resp.Diagnostics.AddAttributeWarning(
+		path.Root("assets"),
+		"Deployment Asset Changes",
+		cs.Summary(),
+	)
+}
+
 // Create creates the resource and sets the initial Terraform state.
 func (r *deploymentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := startSpan(ctx, "deno_deployment.Create")
+	defer endSpan(span, &resp.Diagnostics)
+
 	// Retrieve values from plan
 	var plan deploymentResourceModel
 	diags := req.Plan.Get(ctx, &plan)
@@ -353,8 +636,15 @@ func (r *deploymentResource) Create(ctx context.Context, req resource.CreateRequ
 		return
 	}
 
+	// Write-only values are only available in the config
+	diags = req.Config.GetAttribute(ctx, path.Root("secret_env_vars_wo"), &plan.SecretEnvVarsWO)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Do deployment
-	diags = r.doDeployment(ctx, &plan)
+	diags = r.doDeployment(ctx, &plan, nil)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -366,10 +656,23 @@ func (r *deploymentResource) Create(ctx context.Context, req resource.CreateRequ
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	diags = resp.Identity.Set(ctx, newDeploymentResourceIdentity(r.organizationID, plan.DeploymentID))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Check that the deployment serves traffic
+	diags = r.runHealthCheck(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
 }
 
 // Read refreshes the Terraform state with the latest data.
 func (r *deploymentResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := startSpan(ctx, "deno_deployment.Read")
+	defer endSpan(span, &resp.Diagnostics)
+
 	// Get current state
 	var state deploymentResourceModel
 	diags := req.State.Get(ctx, &state)
@@ -407,7 +710,17 @@ func (r *deploymentResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 	state.Domains = domainSet
+	state.ProjectID = types.StringValue(deployment.JSON200.ProjectId.String())
+	state.CreatedAt = types.StringValue(deployment.JSON200.CreatedAt.Format(time.RFC3339))
 	state.UpdatedAt = types.StringValue(deployment.JSON200.UpdatedAt.Format(time.RFC3339))
+	if state.UploadedAssets.IsNull() {
+		// Imported deployments have no uploaded assets
+		state.UploadedAssets, diags = emptyUploadedAssets()
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
@@ -415,10 +728,16 @@ func (r *deploymentResource) Read(ctx context.Context, req resource.ReadRequest,
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	diags = resp.Identity.Set(ctx, newDeploymentResourceIdentity(r.organizationID, state.DeploymentID))
+	resp.Diagnostics.Append(diags...)
 }
 
 // Update updates the resource and sets the updated Terraform state on success.
 func (r *deploymentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := startSpan(ctx, "deno_deployment.Update")
+	defer endSpan(span, &resp.Diagnostics)
+
 	// Follow the same procedure as Create
 
 	// Retrieve values from plan
@@ -429,8 +748,51 @@ func (r *deploymentResource) Update(ctx context.Context, req resource.UpdateRequ
 		return
 	}
 
+	diags = req.Config.GetAttribute(ctx, path.Root("secret_env_vars_wo"), &plan.SecretEnvVarsWO)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var state deploymentResourceModel
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	redeploy, diags := requiresRedeploy(ctx, req.State, req.Plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !redeploy {
+		tflog.Info(ctx, "Deployment content is unchanged; updating state without creating a new deployment", map[string]any{
+			"deployment_id": plan.DeploymentID.ValueString(),
+		})
+
+		plan.DeploymentID = state.DeploymentID
+		plan.Status = state.Status
+		plan.Domains = state.Domains
+		plan.UploadedAssets = state.UploadedAssets
+		plan.Changeset = state.Changeset
+		plan.CreatedAt = state.CreatedAt
+		plan.UpdatedAt = state.UpdatedAt
+		plan.SecretEnvVarsWO = types.MapNull(types.StringType)
+
+		diags = resp.State.Set(ctx, plan)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		diags = resp.Identity.Set(ctx, newDeploymentResourceIdentity(r.organizationID, plan.DeploymentID))
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
 	// Do deployment
-	diags = r.doDeployment(ctx, &plan)
+	diags = r.doDeployment(ctx, &plan, state.Assets)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -442,11 +804,295 @@ func (r *deploymentResource) Update(ctx context.Context, req resource.UpdateRequ
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	diags = resp.Identity.Set(ctx, newDeploymentResourceIdentity(r.organizationID, plan.DeploymentID))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Check that the deployment serves traffic
+	diags = r.runHealthCheck(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
 }
 
 // Delete deletes the resource and removes the Terraform state on success.
+// Deployments are immutable and can't be deleted, but the custom domains
+// associated with the deployment are detached from it so that they no longer
+// route to it.
 func (r *deploymentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	// noop
+	ctx, span := startSpan(ctx, "deno_deployment.Delete")
+	defer endSpan(span, &resp.Diagnostics)
+
+	// Retrieve values from state
+	var state deploymentResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if state.DeploymentID.IsNull() || state.DeploymentID.IsUnknown() {
+		return
+	}
+
+	_, err := detachDeploymentDomains(ctx, r.client, r.organizationID, state.DeploymentID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Unable to Delete Deployment %s", state.DeploymentID.ValueString()),
+			fmt.Sprintf("Could not detach the custom domains from the deployment: %s", err.Error()),
+		)
+		return
+	}
+}
+
+// ImportState imports the existing deployment into Terraform, either by the
+// deployment ID or by the identity. The API doesn't return the assets, entry
+// point and environment variables of a deployment, so they have to be
+// configured and the first apply after the import creates a new deployment.
+func (r *deploymentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(checkImportOrganization(ctx, req, r.organizationID)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Retrieve import ID or identity and save to deployment_id attribute
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("deployment_id"), path.Root("deployment_id"), req, resp)
+}
+
+// runHealthCheck probes the domains of the deployment if `health_check` is
+// configured.
+func (r *deploymentResource) runHealthCheck(ctx context.Context, plan *deploymentResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if plan.HealthCheck == nil {
+		return diags
+	}
+
+	cfg, diags := plan.HealthCheck.toConfig(ctx)
+	if diags.HasError() {
+		return diags
+	}
+
+	var domains []string
+	diags.Append(plan.Domains.ElementsAs(ctx, &domains, false)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	baseURLs := make([]string, len(domains))
+	for i, d := range domains {
+		baseURLs[i] = fmt.Sprintf("https://%s", d)
+	}
+
+	checker := &healthChecker{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+	if err := checker.check(ctx, baseURLs, cfg); err != nil {
+		diags.AddAttributeError(
+			path.Root("health_check"),
+			"Deployment Health Check Failed",
+			fmt.Sprintf("Deployment ID: %s\n%s", plan.DeploymentID.ValueString(), err.Error()),
+		)
+	}
+
+	return diags
+}
+
+// ValidateConfig reports environment variables that are defined more than once
+// across `env_vars`, `secret_env_vars` and `secret_env_vars_wo`. Each of them
+// is validated by envVarsValidator as well. It also checks `health_check`,
+// which is otherwise only read once the deployment has been created.
+func (r *deploymentResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	resp.Diagnostics.Append(validateHealthCheckConfig(ctx, req)...)
+
+	sources := []envVarSource{}
+	for _, name := range []string{"env_vars", "secret_env_vars", "secret_env_vars_wo"} {
+		var value types.Map
+		diags := req.Config.GetAttribute(ctx, path.Root(name), &value)
+		resp.Diagnostics.Append(diags...)
+		if diags.HasError() {
+			return
+		}
+		if value.IsNull() || value.IsUnknown() {
+			continue
+		}
+
+		// Only the keys matter here, so unknown values are fine
+		vars := map[string]string{}
+		for k := range value.Elements() {
+			vars[k] = ""
+		}
+		sources = append(sources, envVarSource{attribute: name, vars: vars})
+	}
+
+	_, diags := mergeEnvVars(sources...)
+	resp.Diagnostics.Append(diags...)
+}
+
+// validateHealthCheckConfig checks the known attributes of `health_check` in
+// the configuration.
+func validateHealthCheckConfig(ctx context.Context, req resource.ValidateConfigRequest) diag.Diagnostics {
+	var healthCheck types.Object
+	diags := req.Config.GetAttribute(ctx, path.Root("health_check"), &healthCheck)
+	if diags.HasError() || healthCheck.IsNull() || healthCheck.IsUnknown() {
+		return diags
+	}
+
+	var model healthCheckModel
+	diags.Append(healthCheck.As(ctx, &model, basetypes.ObjectAsOptions{})...)
+	if diags.HasError() {
+		return diags
+	}
+	// The headers are not validated, and may hold unknown values
+	model.Headers = types.MapNull(types.StringType)
+
+	_, d := model.toConfig(ctx)
+	diags.Append(d...)
+	return diags
+}
+
+// planSecretEnvVarsWOHash sets `secret_env_vars_wo_hash` in the plan from the
+// configured write-only values and reports whether it differs from the prior
+// state.
+func planSecretEnvVarsWOHash(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	var secretEnvVarsWO types.Map
+	diags.Append(req.Config.GetAttribute(ctx, path.Root("secret_env_vars_wo"), &secretEnvVarsWO)...)
+	if diags.HasError() {
+		return false, diags
+	}
+
+	priorHash := types.StringNull()
+	if !req.State.Raw.IsNull() {
+		diags.Append(req.State.GetAttribute(ctx, path.Root("secret_env_vars_wo_hash"), &priorHash)...)
+		if diags.HasError() {
+			return false, diags
+		}
+	}
+
+	hash := types.StringNull()
+	if secretEnvVarsWO.IsUnknown() {
+		hash = types.StringUnknown()
+	} else if !secretEnvVarsWO.IsNull() {
+		var vars map[string]string
+		diags.Append(secretEnvVarsWO.ElementsAs(ctx, &vars, true)...)
+		if diags.HasError() {
+			return false, diags
+		}
+
+		// The salt is generated once on apply and kept in state, as the plan
+		// must be the same when Terraform plans again during apply.
+		hash = types.StringUnknown()
+		if salt, ok := envVarsHashSalt(priorHash.ValueString()); ok {
+			hash = types.StringValue(hashEnvVars(vars, salt))
+		}
+	}
+
+	diags.Append(resp.Plan.SetAttribute(ctx, path.Root("secret_env_vars_wo_hash"), hash)...)
+	if diags.HasError() || req.State.Raw.IsNull() {
+		return false, diags
+	}
+
+	return !priorHash.Equal(hash), diags
+}
+
+// newSecretEnvVarsWOHash returns the value of `secret_env_vars_wo_hash` for
+// the write-only values, keyed with a new salt.
+func newSecretEnvVarsWOHash(vars map[string]string) (types.String, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	salt, err := newEnvVarsSalt()
+	if err != nil {
+		diags.AddError(
+			"Unable to Hash Write-Only Environment Variables",
+			fmt.Sprintf("Could not generate a salt for `secret_env_vars_wo_hash`: %s", err.Error()),
+		)
+		return types.StringNull(), diags
+	}
+	return types.StringValue(hashEnvVars(vars, salt)), diags
+}
+
+// markDeploymentComputedUnknown marks the attributes that are populated from a
+// newly created deployment as unknown in the plan.
+func markDeploymentComputedUnknown(ctx context.Context, resp *resource.ModifyPlanResponse) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for p, v := range map[string]attr.Value{
+		"deployment_id":   types.StringUnknown(),
+		"status":          types.StringUnknown(),
+		"domains":         types.SetUnknown(types.StringType),
+		"uploaded_assets": types.MapUnknown(types.ObjectType{AttrTypes: uploadedAssetAttrTypes}),
+		"changeset":       types.ObjectUnknown(changesetAttrTypes),
+		"created_at":      types.StringUnknown(),
+		"updated_at":      types.StringUnknown(),
+	} {
+		diags.Append(resp.Plan.SetAttribute(ctx, path.Root(p), v)...)
+	}
+
+	return diags
+}
+
+// attributeGetter is implemented by both tfsdk.State and tfsdk.Plan.
+type attributeGetter interface {
+	GetAttribute(ctx context.Context, p path.Path, target interface{}) diag.Diagnostics
+}
+
+// requiresRedeploy reports whether the planned values differ from the prior
+// state in a way that requires a new deployment to be created. Assets are
+// compared semantically with assetsSemanticallyEqual.
+func requiresRedeploy(ctx context.Context, state, plan attributeGetter) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	for _, name := range []string{"project_id", "entry_point_url", "import_map_url", "lock_file_url", "secret_env_vars_wo_hash"} {
+		var prior, planned types.String
+		diags.Append(state.GetAttribute(ctx, path.Root(name), &prior)...)
+		diags.Append(plan.GetAttribute(ctx, path.Root(name), &planned)...)
+		if diags.HasError() {
+			return true, diags
+		}
+		if !prior.Equal(planned) {
+			return true, diags
+		}
+	}
+
+	var priorCompilerOptions, plannedCompilerOptions types.Object
+	diags.Append(state.GetAttribute(ctx, path.Root("compiler_options"), &priorCompilerOptions)...)
+	diags.Append(plan.GetAttribute(ctx, path.Root("compiler_options"), &plannedCompilerOptions)...)
+	if diags.HasError() || !priorCompilerOptions.Equal(plannedCompilerOptions) {
+		return true, diags
+	}
+
+	for _, name := range []string{"env_vars", "secret_env_vars"} {
+		var prior, planned types.Map
+		diags.Append(state.GetAttribute(ctx, path.Root(name), &prior)...)
+		diags.Append(plan.GetAttribute(ctx, path.Root(name), &planned)...)
+		if diags.HasError() {
+			return true, diags
+		}
+		if !prior.Equal(planned) {
+			return true, diags
+		}
+	}
+
+	var priorAssetsValue, plannedAssetsValue types.Map
+	diags.Append(state.GetAttribute(ctx, path.Root("assets"), &priorAssetsValue)...)
+	diags.Append(plan.GetAttribute(ctx, path.Root("assets"), &plannedAssetsValue)...)
+	if diags.HasError() || plannedAssetsValue.IsUnknown() {
+		return true, diags
+	}
+
+	var priorAssets, plannedAssets map[string]asset
+	diags.Append(priorAssetsValue.ElementsAs(ctx, &priorAssets, false)...)
+	diags.Append(plannedAssetsValue.ElementsAs(ctx, &plannedAssets, false)...)
+	if diags.HasError() {
+		return true, diags
+	}
+
+	equal, d := assetsSemanticallyEqual(priorAssets, plannedAssets)
+	diags.Append(d...)
+	return !equal, diags
 }
 
 // Configure adds the provider configured client to the resource.
@@ -466,11 +1112,19 @@ func (r *deploymentResource) Configure(_ context.Context, req resource.Configure
 		return
 	}
 
+	resp.Diagnostics.Append(providerData.connect()...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	r.client = providerData.client
 	r.organizationID = providerData.organizationID
 }
 
-func (r *deploymentResource) doDeployment(ctx context.Context, plan *deploymentResourceModel) diag.Diagnostics {
+// doDeployment creates a new deployment of the planned values and sets the
+// computed values in the plan. priorAssets are the assets of the previous
+// deployment, if any, to compute the changeset from.
+func (r *deploymentResource) doDeployment(ctx context.Context, plan *deploymentResourceModel, priorAssets map[string]asset) diag.Diagnostics {
 	accumulatedDiags := diag.Diagnostics{}
 
 	projectID, err := uuid.Parse(plan.ProjectID.ValueString())
@@ -482,14 +1136,28 @@ func (r *deploymentResource) doDeployment(ctx context.Context, plan *deploymentR
 		return accumulatedDiags
 	}
 
-	assets, diag := prepareAssetsForUpload(plan.Assets)
+	assets, diag := prepareAssetsForUpload(ctx, plan.Assets)
 	accumulatedDiags.Append(diag)
 	if accumulatedDiags.HasError() {
 		return accumulatedDiags
 	}
 
-	var envVars map[string]string
-	diags := plan.EnvVars.ElementsAs(ctx, &envVars, true)
+	var plainEnvVars, secretEnvVars, secretEnvVarsWO map[string]string
+	diags := plan.EnvVars.ElementsAs(ctx, &plainEnvVars, true)
+	accumulatedDiags.Append(diags...)
+	diags = plan.SecretEnvVars.ElementsAs(ctx, &secretEnvVars, true)
+	accumulatedDiags.Append(diags...)
+	diags = plan.SecretEnvVarsWO.ElementsAs(ctx, &secretEnvVarsWO, true)
+	accumulatedDiags.Append(diags...)
+	if accumulatedDiags.HasError() {
+		return accumulatedDiags
+	}
+
+	envVars, diags := mergeEnvVars(
+		envVarSource{attribute: "env_vars", vars: plainEnvVars},
+		envVarSource{attribute: "secret_env_vars", vars: secretEnvVars},
+		envVarSource{attribute: "secret_env_vars_wo", vars: secretEnvVarsWO},
+	)
 	accumulatedDiags.Append(diags...)
 	if accumulatedDiags.HasError() {
 		return accumulatedDiags
@@ -608,13 +1276,7 @@ Build logs:
 	plan.Domains = domainSet
 
 	// TODO: we haven't implemented the logic to avoid duplicate uploads
-	uploadedAssets, diags := types.MapValue(types.ObjectType{
-		AttrTypes: map[string]attr.Type{
-			"path":       types.StringType,
-			"git_sha1":   types.StringType,
-			"updated_at": types.StringType,
-		},
-	}, map[string]attr.Value{})
+	uploadedAssets, diags := emptyUploadedAssets()
 	accumulatedDiags.Append(diags...)
 	if accumulatedDiags.HasError() {
 		return accumulatedDiags
@@ -624,5 +1286,40 @@ Build logs:
 	plan.CreatedAt = types.StringValue(deployment.JSON200.CreatedAt.Format(time.RFC3339))
 	plan.UpdatedAt = types.StringValue(deployment.JSON200.UpdatedAt.Format(time.RFC3339))
 
+	// The changeset is unknown in the plan when some of the assets were not
+	// known at plan time, or when only the write-only values have changed.
+	// All of them are known now.
+	if plan.Changeset.IsUnknown() {
+		cs, ok, diags := diffAssets(priorAssets, plan.Assets)
+		accumulatedDiags.Append(diags...)
+		if accumulatedDiags.HasError() {
+			return accumulatedDiags
+		}
+		if !ok {
+			cs = newAssetChangeset()
+		}
+		plan.Changeset, diags = cs.ToObject(ctx)
+		accumulatedDiags.Append(diags...)
+		if accumulatedDiags.HasError() {
+			return accumulatedDiags
+		}
+	}
+
+	// The write-only values may be unknown at plan time, e.g. when they come
+	// from another resource.
+	if plan.SecretEnvVarsWOHash.IsUnknown() {
+		plan.SecretEnvVarsWOHash = types.StringNull()
+		if secretEnvVarsWO != nil {
+			plan.SecretEnvVarsWOHash, diags = newSecretEnvVarsWOHash(secretEnvVarsWO)
+			accumulatedDiags.Append(diags...)
+			if accumulatedDiags.HasError() {
+				return accumulatedDiags
+			}
+		}
+	}
+
+	// Never persist write-only values
+	plan.SecretEnvVarsWO = types.MapNull(types.StringType)
+
 	return accumulatedDiags
 }
@@ -15,7 +15,10 @@ import (
 	"unicode/utf8"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
 )
 
 func TestAccDeployment_SingleFile(t *testing.T) {
@@ -85,6 +88,41 @@ func TestAccDeployment_SingleFileWithoutCompilerOptions(t *testing.T) {
 	})
 }
 
+func TestAccDeployment_HealthCheck(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccDeploymentDestroy(t),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					resource "deno_project" "test" {}
+
+					data "deno_assets" "test" {
+						path = "./testdata/single-file"
+						pattern = "main.ts"
+					}
+
+					resource "deno_deployment" "test" {
+						project_id = deno_project.test.id
+						entry_point_url = "main.ts"
+						assets = data.deno_assets.test.output
+						env_vars = {}
+						health_check = {
+							path = "/"
+							body_regex = "^Hello world$"
+							interval = "2s"
+						}
+					}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("deno_deployment.test", "status", "success"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccDeployment_TwoDeployments(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheck(t) },
@@ -362,6 +400,187 @@ func TestAccDeployment_EnvVars(t *testing.T) {
 	})
 }
 
+func TestAccDeployment_SecretEnvVars(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccDeploymentDestroy(t),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					resource "deno_project" "test" {}
+
+					data "deno_assets" "test" {
+						path = "testdata/env_var"
+						pattern = "main.ts"
+					}
+
+					resource "deno_deployment" "test" {
+						project_id = deno_project.test.id
+						entry_point_url = "main.ts"
+						assets = data.deno_assets.test.output
+						env_vars = {}
+						secret_env_vars = {
+							"FOO" = "Secret"
+						}
+					}
+				`,
+				Check: resource.ComposeTestCheckFunc(testAccCheckDeploymentDomains(t, "deno_deployment.test", []responseTest{
+					{
+						path:     "/",
+						expected: []byte("Hello Secret"),
+					},
+				})),
+			},
+		},
+	})
+}
+
+func TestAccDeployment_WriteOnlySecretEnvVars(t *testing.T) {
+	config := func(value string) string {
+		return fmt.Sprintf(`
+			resource "deno_project" "test" {}
+
+			data "deno_assets" "test" {
+				path = "testdata/env_var"
+				pattern = "main.ts"
+			}
+
+			resource "deno_deployment" "test" {
+				project_id = deno_project.test.id
+				entry_point_url = "main.ts"
+				assets = data.deno_assets.test.output
+				env_vars = {}
+				secret_env_vars_wo = {
+					"FOO" = "%s"
+				}
+			}
+		`, value)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccDeploymentDestroy(t),
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_11_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config("WriteOnly"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckNoResourceAttr("deno_deployment.test", "secret_env_vars_wo.%"),
+					resource.TestCheckResourceAttrSet("deno_deployment.test", "secret_env_vars_wo_hash"),
+					testAccCheckDeploymentDomains(t, "deno_deployment.test", []responseTest{
+						{
+							path:     "/",
+							expected: []byte("Hello WriteOnly"),
+						},
+					}),
+				),
+			},
+			{
+				// A change of the write-only value triggers a new deployment
+				// without any asset change
+				Config: config("Rotated"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("deno_deployment.test", plancheck.ResourceActionUpdate),
+						plancheck.ExpectUnknownValue("deno_deployment.test", tfjsonpath.New("changeset")),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("deno_deployment.test", "changeset.added.#", "0"),
+					resource.TestCheckResourceAttr("deno_deployment.test", "changeset.modified.#", "0"),
+					resource.TestCheckResourceAttr("deno_deployment.test", "changeset.removed.#", "0"),
+					resource.TestCheckResourceAttrSet("deno_deployment.test", "secret_env_vars_wo_hash"),
+					testAccCheckDeploymentDomains(t, "deno_deployment.test", []responseTest{
+						{
+							path:     "/",
+							expected: []byte("Hello Rotated"),
+						},
+					}),
+				),
+			},
+			{
+				// The same write-only value doesn't trigger another deployment
+				Config: config("Rotated"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestAccDeployment_ConflictingEnvVars(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					resource "deno_deployment" "test" {
+						project_id = "00000000-0000-0000-0000-000000000000"
+						entry_point_url = "main.ts"
+						assets = {}
+						env_vars = {
+							"FOO" = "plain"
+						}
+						secret_env_vars = {
+							"FOO" = "secret"
+						}
+					}
+				`,
+				ExpectError: regexp.MustCompile("Conflicting Environment Variable"),
+			},
+			{
+				// The names of the variables are still checked when a value
+				// is too large
+				Config: `
+					resource "deno_deployment" "test" {
+						project_id = "00000000-0000-0000-0000-000000000000"
+						entry_point_url = "main.ts"
+						assets = {}
+						env_vars = {
+							"BIG" = format("%016385d", 0)
+							"FOO" = "plain"
+						}
+						secret_env_vars = {
+							"FOO" = "secret"
+						}
+					}
+				`,
+				ExpectError: regexp.MustCompile("Conflicting Environment Variable"),
+			},
+		},
+	})
+}
+
+func TestAccDeployment_ReservedEnvVar(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					resource "deno_deployment" "test" {
+						project_id = "00000000-0000-0000-0000-000000000000"
+						entry_point_url = "main.ts"
+						assets = {}
+						env_vars = {
+							"DENO_REGION" = "us-east4"
+						}
+					}
+				`,
+				ExpectError: regexp.MustCompile(`The environment variable name "DENO_REGION" is reserved`),
+			},
+		},
+	})
+}
+
 func TestAccDeployment_ConfigAutoDiscovery(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheck(t) },
@@ -430,6 +649,46 @@ func TestAccDeployment_InlineAsset_Utf8(t *testing.T) {
 	})
 }
 
+func TestAccDeployment_InlineAsset_ComputedContent(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccDeploymentDestroy(t),
+		Steps: []resource.TestStep{
+			{
+				// The content isn't known until the project is created, so
+				// the changeset is computed on apply
+				Config: `
+					resource "deno_project" "test" {}
+
+					resource "deno_deployment" "test" {
+						project_id = deno_project.test.id
+						entry_point_url = "main.ts"
+						assets = {
+							"main.ts" = {
+								kind = "file"
+								content = "Deno.serve(() => new Response('Hello ${deno_project.test.name}'))"
+							}
+						}
+						env_vars = {}
+					}
+				`,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectUnknownValue("deno_deployment.test", tfjsonpath.New("changeset")),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("deno_deployment.test", "changeset.added.#", "1"),
+					resource.TestCheckResourceAttr("deno_deployment.test", "changeset.added.0", "main.ts"),
+					resource.TestCheckResourceAttr("deno_deployment.test", "changeset.modified.#", "0"),
+					resource.TestCheckResourceAttr("deno_deployment.test", "changeset.removed.#", "0"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccDeployment_InlineAsset_Base64(t *testing.T) {
 	expectedBinary, err := os.ReadFile("testdata/binary/computer_screen_programming.png")
 	if err != nil {
@@ -758,6 +1017,74 @@ func TestAccDeployment_ChangeFileAfterFirstDeployment(t *testing.T) {
 	})
 }
 
+func TestAccDeployment_MoveAssetsDirectory(t *testing.T) {
+	dir1, err := os.MkdirTemp("", "TestAccDeployment_MoveAssetsDirectory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir1)
+	dir2, err := os.MkdirTemp("", "TestAccDeployment_MoveAssetsDirectory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir2)
+
+	content := []byte("Deno.serve(() => new Response('Hello world'))")
+	for _, dir := range []string{dir1, dir2} {
+		if err = os.WriteFile(filepath.Join(dir, "main.ts"), content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config := func(dir string) string {
+		return fmt.Sprintf(`
+			resource "deno_project" "test" {}
+
+			data "deno_assets" "test" {
+				path = "%s"
+				pattern = "*.ts"
+			}
+
+			resource "deno_deployment" "test" {
+				project_id = deno_project.test.id
+				entry_point_url = "main.ts"
+				assets = data.deno_assets.test.output
+				env_vars = {}
+			}
+		`, dir)
+	}
+
+	var deploymentID string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccDeploymentDestroy(t),
+		Steps: []resource.TestStep{
+			{
+				Config: config(dir1),
+				Check: resource.TestCheckResourceAttrWith("deno_deployment.test", "deployment_id", func(value string) error {
+					deploymentID = value
+					return nil
+				}),
+			},
+			{
+				// Only content_source_path changes; no new deployment is created
+				Config: config(dir2),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("deno_deployment.test", "assets.main.ts.content_source_path", filepath.Join(dir2, "main.ts")),
+					resource.TestCheckResourceAttrWith("deno_deployment.test", "deployment_id", func(value string) error {
+						if value != deploymentID {
+							return fmt.Errorf("expected deployment %s to be kept, but got %s", deploymentID, value)
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
 type responseTest struct {
 	path     string
 	expected []byte
@@ -0,0 +1,231 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// deploymentSchemaVersion is the version of the deno_deployment schema. Bump
+// it when changing the schema in a way that the state written by the prior
+// version can't be read as is, and add an upgrader from the prior version to
+// UpgradeState.
+//
+// Version 1 guarantees that uploaded_assets is a map, which was null in the
+// state of imported deployments in version 0.
+const deploymentSchemaVersion = 1
+
+// UpgradeState returns the upgraders of the state written by the prior
+// versions of the schema. Each upgrader upgrades the state to the current
+// version directly.
+func (r *deploymentResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	schemaV0 := deploymentSchemaV0(ctx)
+
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema:   &schemaV0,
+			StateUpgrader: upgradeDeploymentStateFromV0,
+		},
+	}
+}
+
+// deploymentResourceModelV0 maps the version 0 of the schema. Nested
+// attributes are kept as generic values so that later changes of the nested
+// models don't affect it.
+type deploymentResourceModelV0 struct {
+	DeploymentID        types.String   `tfsdk:"deployment_id"`
+	ProjectID           types.String   `tfsdk:"project_id"`
+	Status              types.String   `tfsdk:"status"`
+	Domains             types.Set      `tfsdk:"domains"`
+	EntryPointURL       types.String   `tfsdk:"entry_point_url"`
+	ImportMapURL        types.String   `tfsdk:"import_map_url"`
+	LockFileURL         types.String   `tfsdk:"lock_file_url"`
+	CompilerOptions     types.Object   `tfsdk:"compiler_options"`
+	Assets              types.Map      `tfsdk:"assets"`
+	UploadedAssets      types.Map      `tfsdk:"uploaded_assets"`
+	EnvVars             types.Map      `tfsdk:"env_vars"`
+	SecretEnvVars       types.Map      `tfsdk:"secret_env_vars"`
+	SecretEnvVarsWO     types.Map      `tfsdk:"secret_env_vars_wo"`
+	SecretEnvVarsWOHash types.String   `tfsdk:"secret_env_vars_wo_hash"`
+	Changeset           types.Object   `tfsdk:"changeset"`
+	HealthCheck         types.Object   `tfsdk:"health_check"`
+	CreatedAt           types.String   `tfsdk:"created_at"`
+	UpdatedAt           types.String   `tfsdk:"updated_at"`
+	Timeouts            timeouts.Value `tfsdk:"timeouts"`
+}
+
+// upgradeDeploymentStateFromV0 upgrades the state written by the version 0 of
+// the schema.
+func upgradeDeploymentStateFromV0(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var prior deploymentResourceModelV0
+	diags := req.State.Get(ctx, &prior)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	upgraded := deploymentResourceModel{
+		DeploymentID:        prior.DeploymentID,
+		ProjectID:           prior.ProjectID,
+		Status:              prior.Status,
+		Domains:             prior.Domains,
+		EntryPointURL:       prior.EntryPointURL,
+		ImportMapURL:        prior.ImportMapURL,
+		LockFileURL:         prior.LockFileURL,
+		UploadedAssets:      prior.UploadedAssets,
+		EnvVars:             prior.EnvVars,
+		SecretEnvVars:       prior.SecretEnvVars,
+		SecretEnvVarsWO:     types.MapNull(types.StringType),
+		SecretEnvVarsWOHash: prior.SecretEnvVarsWOHash,
+		Changeset:           prior.Changeset,
+		CreatedAt:           prior.CreatedAt,
+		UpdatedAt:           prior.UpdatedAt,
+		Timeouts:            prior.Timeouts,
+	}
+
+	if !prior.Assets.IsNull() {
+		diags = prior.Assets.ElementsAs(ctx, &upgraded.Assets, false)
+		resp.Diagnostics.Append(diags...)
+	}
+	if !prior.CompilerOptions.IsNull() {
+		upgraded.CompilerOptions = &compilerOptionsModel{}
+		diags = prior.CompilerOptions.As(ctx, upgraded.CompilerOptions, basetypes.ObjectAsOptions{})
+		resp.Diagnostics.Append(diags...)
+	}
+	if !prior.HealthCheck.IsNull() {
+		upgraded.HealthCheck = &healthCheckModel{}
+		diags = prior.HealthCheck.As(ctx, upgraded.HealthCheck, basetypes.ObjectAsOptions{})
+		resp.Diagnostics.Append(diags...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Imported deployments had no uploaded assets
+	if upgraded.UploadedAssets.IsNull() {
+		upgraded.UploadedAssets, diags = emptyUploadedAssets()
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	diags = resp.State.Set(ctx, upgraded)
+	resp.Diagnostics.Append(diags...)
+}
+
+// emptyUploadedAssets returns the value of uploaded_assets without any asset.
+func emptyUploadedAssets() (types.Map, diag.Diagnostics) {
+	return types.MapValue(types.ObjectType{AttrTypes: uploadedAssetAttrTypes}, map[string]attr.Value{})
+}
+
+// deploymentSchemaV0 returns the version 0 of the schema. It must not be
+// changed, as it is used to read the state written by that version.
+func deploymentSchemaV0(ctx context.Context) schema.Schema {
+	return schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"deployment_id": schema.StringAttribute{Computed: true},
+			"project_id":    schema.StringAttribute{Required: true},
+			"status":        schema.StringAttribute{Computed: true},
+			"domains": schema.SetAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"entry_point_url": schema.StringAttribute{Required: true},
+			"import_map_url":  schema.StringAttribute{Optional: true},
+			"lock_file_url":   schema.StringAttribute{Optional: true},
+			"compiler_options": schema.SingleNestedAttribute{
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"jsx":                  schema.StringAttribute{Optional: true},
+					"jsx_factory":          schema.StringAttribute{Optional: true},
+					"jsx_fragment_factory": schema.StringAttribute{Optional: true},
+					"jsx_import_source":    schema.StringAttribute{Optional: true},
+				},
+			},
+			"assets": schema.MapNestedAttribute{
+				Required: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"kind":                schema.StringAttribute{Required: true},
+						"content_source_path": schema.StringAttribute{Optional: true},
+						"target":              schema.StringAttribute{Optional: true},
+						"git_sha1":            schema.StringAttribute{Optional: true},
+						"content":             schema.StringAttribute{Optional: true},
+						"encoding":            schema.StringAttribute{Optional: true},
+					},
+				},
+			},
+			"uploaded_assets": schema.MapNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"path":       schema.StringAttribute{Computed: true},
+						"git_sha1":   schema.StringAttribute{Computed: true},
+						"updated_at": schema.StringAttribute{Computed: true},
+					},
+				},
+			},
+			"env_vars": schema.MapAttribute{
+				Required:    true,
+				ElementType: types.StringType,
+			},
+			"secret_env_vars": schema.MapAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				ElementType: types.StringType,
+			},
+			"secret_env_vars_wo": schema.MapAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				WriteOnly:   true,
+				ElementType: types.StringType,
+			},
+			"secret_env_vars_wo_hash": schema.StringAttribute{Computed: true},
+			"changeset": schema.SingleNestedAttribute{
+				Computed: true,
+				Attributes: map[string]schema.Attribute{
+					"added": schema.ListAttribute{
+						Computed:    true,
+						ElementType: types.StringType,
+					},
+					"modified": schema.ListAttribute{
+						Computed:    true,
+						ElementType: types.StringType,
+					},
+					"removed": schema.ListAttribute{
+						Computed:    true,
+						ElementType: types.StringType,
+					},
+					"upload_bytes": schema.Int64Attribute{Computed: true},
+					"summary":      schema.StringAttribute{Computed: true},
+				},
+			},
+			"health_check": schema.SingleNestedAttribute{
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"path":            schema.StringAttribute{Optional: true},
+					"expected_status": schema.Int64Attribute{Optional: true},
+					"body_regex":      schema.StringAttribute{Optional: true},
+					"headers": schema.MapAttribute{
+						Optional:    true,
+						ElementType: types.StringType,
+					},
+					"retries":  schema.Int64Attribute{Optional: true},
+					"interval": schema.StringAttribute{Optional: true},
+				},
+			},
+			"created_at": schema.StringAttribute{Computed: true},
+			"updated_at": schema.StringAttribute{Computed: true},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+				Create: true,
+			}),
+		},
+	}
+}
@@ -0,0 +1,176 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// upgradeResourceState upgrades the raw state of the given version through
+// the provider server, the same way as Terraform does, and returns the
+// upgraded state.
+func upgradeResourceState(t *testing.T, r resource.Resource, typeName string, version int64, rawState map[string]any) tfsdk.State {
+	t.Helper()
+	ctx := context.Background()
+
+	b, err := json.Marshal(rawState)
+	if err != nil {
+		t.Fatalf("failed to marshal the raw state: %s", err)
+	}
+
+	server, err := providerserver.NewProtocol6WithError(New("test")())()
+	if err != nil {
+		t.Fatalf("failed to create provider server: %s", err)
+	}
+	resp, err := server.UpgradeResourceState(ctx, &tfprotov6.UpgradeResourceStateRequest{
+		TypeName: typeName,
+		Version:  version,
+		RawState: &tfprotov6.RawState{JSON: b},
+	})
+	if err != nil {
+		t.Fatalf("UpgradeResourceState() returned unexpected error: %s", err)
+	}
+	for _, d := range resp.Diagnostics {
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+			t.Fatalf("UpgradeResourceState() returned unexpected diagnostic: %s: %s", d.Summary, d.Detail)
+		}
+	}
+
+	schemaResp := &resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, schemaResp)
+	value, err := resp.UpgradedState.Unmarshal(schemaResp.Schema.Type().TerraformType(ctx))
+	if err != nil {
+		t.Fatalf("failed to unmarshal the upgraded state: %s", err)
+	}
+	return tfsdk.State{Schema: schemaResp.Schema, Raw: value}
+}
+
+func TestDeploymentResource_UpgradeStateFromV0(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("deployed", func(t *testing.T) {
+		state := upgradeResourceState(t, &deploymentResource{}, "deno_deployment", 0, map[string]any{
+			"deployment_id":   "abcdefghijkl",
+			"project_id":      "6bd4b6c2-0a3f-4f1c-9d2b-7a1d2f3c4e5f",
+			"status":          "success",
+			"domains":         []string{"my-project-abcdefghijkl.deno.dev"},
+			"entry_point_url": "main.ts",
+			"compiler_options": map[string]any{
+				"jsx": "react-jsx",
+			},
+			"assets": map[string]any{
+				"main.ts": map[string]any{
+					"kind":                "file",
+					"content_source_path": "src/main.ts",
+					"git_sha1":            "0123456789abcdef0123456789abcdef01234567",
+				},
+			},
+			"uploaded_assets": map[string]any{},
+			"env_vars":        map[string]any{"FOO": "foo"},
+			"health_check": map[string]any{
+				"path":    "/healthz",
+				"retries": 3,
+			},
+			"created_at": "2025-01-01T00:00:00Z",
+			"updated_at": "2025-01-02T00:00:00Z",
+			// Attributes that were dropped are ignored
+			"removed_attribute": "ignored",
+		})
+
+		var upgraded deploymentResourceModel
+		diags := state.Get(ctx, &upgraded)
+		if diags.HasError() {
+			t.Fatalf("failed to read the upgraded state: %v", diags)
+		}
+
+		if upgraded.DeploymentID.ValueString() != "abcdefghijkl" || upgraded.Status.ValueString() != "success" {
+			t.Errorf("deployment_id = %s, status = %s", upgraded.DeploymentID, upgraded.Status)
+		}
+		if len(upgraded.Domains.Elements()) != 1 {
+			t.Errorf("domains = %s", upgraded.Domains)
+		}
+		if a, ok := upgraded.Assets["main.ts"]; !ok || a.LocalFilePath.ValueString() != "src/main.ts" || !a.Content.IsNull() {
+			t.Errorf("assets = %v", upgraded.Assets)
+		}
+		if upgraded.CompilerOptions == nil || upgraded.CompilerOptions.JSX.ValueString() != "react-jsx" || !upgraded.CompilerOptions.JSXFactory.IsNull() {
+			t.Errorf("compiler_options = %v", upgraded.CompilerOptions)
+		}
+		if upgraded.HealthCheck == nil || upgraded.HealthCheck.Path.ValueString() != "/healthz" || upgraded.HealthCheck.Retries.ValueInt64() != 3 {
+			t.Errorf("health_check = %v", upgraded.HealthCheck)
+		}
+		if upgraded.EnvVars.Elements()["FOO"].String() != `"foo"` {
+			t.Errorf("env_vars = %s", upgraded.EnvVars)
+		}
+		// Attributes missing in old states are null
+		if !upgraded.SecretEnvVars.IsNull() || !upgraded.SecretEnvVarsWOHash.IsNull() || !upgraded.Changeset.IsNull() {
+			t.Errorf("secret_env_vars = %s, secret_env_vars_wo_hash = %s, changeset = %s", upgraded.SecretEnvVars, upgraded.SecretEnvVarsWOHash, upgraded.Changeset)
+		}
+		if upgraded.UploadedAssets.IsNull() || len(upgraded.UploadedAssets.Elements()) != 0 {
+			t.Errorf("uploaded_assets = %s, want an empty map", upgraded.UploadedAssets)
+		}
+	})
+
+	t.Run("imported", func(t *testing.T) {
+		state := upgradeResourceState(t, &deploymentResource{}, "deno_deployment", 0, map[string]any{
+			"deployment_id": "abcdefghijkl",
+			"project_id":    "6bd4b6c2-0a3f-4f1c-9d2b-7a1d2f3c4e5f",
+			"status":        "success",
+		})
+
+		var upgraded deploymentResourceModel
+		diags := state.Get(ctx, &upgraded)
+		if diags.HasError() {
+			t.Fatalf("failed to read the upgraded state: %v", diags)
+		}
+
+		if upgraded.UploadedAssets.IsNull() || len(upgraded.UploadedAssets.Elements()) != 0 {
+			t.Errorf("uploaded_assets = %s, want an empty map", upgraded.UploadedAssets)
+		}
+		if upgraded.Assets != nil || upgraded.CompilerOptions != nil || upgraded.HealthCheck != nil {
+			t.Errorf("assets = %v, compiler_options = %v, health_check = %v, want null", upgraded.Assets, upgraded.CompilerOptions, upgraded.HealthCheck)
+		}
+	})
+}
+
+// TestResourceStateUpgraders checks that every resource can upgrade the state
+// of all the prior versions of its schema.
+func TestResourceStateUpgraders(t *testing.T) {
+	ctx := context.Background()
+	p := &deployProvider{}
+
+	for _, newResource := range p.Resources(ctx) {
+		r := newResource()
+		metadataResp := &resource.MetadataResponse{}
+		r.Metadata(ctx, resource.MetadataRequest{ProviderTypeName: "deno"}, metadataResp)
+
+		t.Run(metadataResp.TypeName, func(t *testing.T) {
+			schemaResp := &resource.SchemaResponse{}
+			r.Schema(ctx, resource.SchemaRequest{}, schemaResp)
+
+			withUpgradeState, ok := r.(resource.ResourceWithUpgradeState)
+			if !ok {
+				t.Fatal("resource does not implement ResourceWithUpgradeState")
+			}
+			upgraders := withUpgradeState.UpgradeState(ctx)
+
+			if int64(len(upgraders)) != schemaResp.Schema.Version {
+				t.Errorf("%d upgraders for schema version %d", len(upgraders), schemaResp.Schema.Version)
+			}
+			for version := range schemaResp.Schema.Version {
+				upgrader, ok := upgraders[version]
+				if !ok {
+					t.Errorf("no upgrader from version %d", version)
+					continue
+				}
+				if upgrader.PriorSchema == nil {
+					t.Errorf("upgrader from version %d has no prior schema", version)
+				}
+			}
+		})
+	}
+}
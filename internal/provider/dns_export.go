@@ -0,0 +1,169 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+	"terraform-provider-deno/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// dnsExportRecord is a DNS record with its name made relative to the zone
+// apex.
+type dnsExportRecord struct {
+	Type string
+	// Name is relative to the zone apex, `@` being the apex itself. It is the
+	// same as FQDN if no zone is given.
+	Name    string
+	FQDN    string
+	Content string
+}
+
+var dnsExportRecordAttrTypes = map[string]attr.Type{
+	"type":    types.StringType,
+	"name":    types.StringType,
+	"fqdn":    types.StringType,
+	"content": types.StringType,
+}
+
+// exportDNSRecords prepares the records of the domain for the export. An
+// empty zone keeps the names fully qualified.
+func exportDNSRecords(domain, zone string, records []client.DnsRecord) []dnsExportRecord {
+	zone = strings.ToLower(strings.TrimSuffix(zone, "."))
+
+	exported := make([]dnsExportRecord, len(records))
+	for i, record := range records {
+		fqdn := dnsRecordFQDN(domain, record.Name)
+		name := fqdn
+		switch {
+		case zone == "":
+		case fqdn == zone:
+			name = "@"
+		case strings.HasSuffix(fqdn, "."+zone):
+			name = strings.TrimSuffix(fqdn, "."+zone)
+		}
+		exported[i] = dnsExportRecord{
+			Type:    strings.ToUpper(record.Type),
+			Name:    name,
+			FQDN:    fqdn,
+			Content: record.Content,
+		}
+	}
+	return exported
+}
+
+// dnsZoneFileFragment renders the records as RFC 1035 resource records. The
+// TTL is omitted so that the $TTL of the enclosing zone file applies. Without
+// a zone the owner names are absolute.
+func dnsZoneFileFragment(records []dnsExportRecord, zone string) string {
+	var sb strings.Builder
+	for _, record := range records {
+		owner := record.Name
+		if zone == "" {
+			owner = record.FQDN + "."
+		}
+
+		rdata := record.Content
+		switch record.Type {
+		case "CNAME":
+			rdata = strings.TrimSuffix(rdata, ".") + "."
+		case "TXT":
+			rdata = quoteTXT(strings.Trim(rdata, `"`))
+		}
+
+		fmt.Fprintf(&sb, "%s\tIN\t%s\t%s\n", owner, record.Type, rdata)
+	}
+	return sb.String()
+}
+
+// quoteTXT renders the text as an RFC 1035 character-string. The quotes and
+// backslashes are escaped with a backslash, and the other non-printable bytes
+// with their decimal value, e.g. \009 for a tab.
+func quoteTXT(text string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; {
+		case c == '"' || c == '\\':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		case c < 0x20 || c >= 0x7f:
+			fmt.Fprintf(&sb, "\\%03d", c)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+func (r dnsExportRecord) toObject() (types.Object, diag.Diagnostics) {
+	return types.ObjectValue(dnsExportRecordAttrTypes, map[string]attr.Value{
+		"type":    types.StringValue(r.Type),
+		"name":    types.StringValue(r.Name),
+		"fqdn":    types.StringValue(r.FQDN),
+		"content": types.StringValue(r.Content),
+	})
+}
+
+// dnsRecordsByTypeValue groups the records by type, e.g. `{ A = [...],
+// TXT = [...] }`.
+func dnsRecordsByTypeValue(records []dnsExportRecord) (types.Map, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	listType := types.ListType{ElemType: types.ObjectType{AttrTypes: dnsExportRecordAttrTypes}}
+
+	grouped := map[string][]attr.Value{}
+	for _, record := range records {
+		obj, d := record.toObject()
+		diags.Append(d...)
+		if diags.HasError() {
+			return types.MapNull(listType), diags
+		}
+		grouped[record.Type] = append(grouped[record.Type], obj)
+	}
+
+	elements := map[string]attr.Value{}
+	for recordType, objs := range grouped {
+		list, d := types.ListValue(listType.ElemType, objs)
+		diags.Append(d...)
+		if diags.HasError() {
+			return types.MapNull(listType), diags
+		}
+		elements[recordType] = list
+	}
+
+	m, d := types.MapValue(listType, elements)
+	diags.Append(d...)
+	return m, diags
+}
+
+// dnsRecordsForEachValue returns the records keyed by `<type> <name>`, which
+// can be passed to for_each as is. Further records with the same type and
+// name get a ` 2`, ` 3`, ... suffix.
+func dnsRecordsForEachValue(records []dnsExportRecord) (types.Map, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	objectType := types.ObjectType{AttrTypes: dnsExportRecordAttrTypes}
+
+	seen := map[string]int{}
+	elements := map[string]attr.Value{}
+	for _, record := range records {
+		key := record.Type + " " + record.Name
+		seen[key]++
+		if n := seen[key]; n > 1 {
+			key = fmt.Sprintf("%s %d", key, n)
+		}
+
+		obj, d := record.toObject()
+		diags.Append(d...)
+		if diags.HasError() {
+			return types.MapNull(objectType), diags
+		}
+		elements[key] = obj
+	}
+
+	m, d := types.MapValue(objectType, elements)
+	diags.Append(d...)
+	return m, diags
+}
@@ -0,0 +1,125 @@
+package provider
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"terraform-provider-deno/client"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var testDNSRecords = []client.DnsRecord{
+	{Type: "a", Name: "foo.example.com", Content: "10.0.0.1"},
+	{Type: "a", Name: "foo.example.com", Content: "10.0.0.2"},
+	{Type: "aaaa", Name: "foo.example.com", Content: "2001:db8::1"},
+	{Type: "cname", Name: "_acme-challenge.foo.example.com", Content: "foo.acme.deno.dev"},
+	{Type: "txt", Name: "_deno", Content: "token"},
+}
+
+func TestExportDNSRecords(t *testing.T) {
+	tests := []struct {
+		name     string
+		zone     string
+		expected []string
+	}{
+		{
+			name:     "no zone",
+			zone:     "",
+			expected: []string{"foo.example.com", "foo.example.com", "foo.example.com", "_acme-challenge.foo.example.com", "_deno.foo.example.com"},
+		},
+		{
+			name:     "parent zone",
+			zone:     "example.com",
+			expected: []string{"foo", "foo", "foo", "_acme-challenge.foo", "_deno.foo"},
+		},
+		{
+			name:     "domain as zone",
+			zone:     "foo.example.com.",
+			expected: []string{"@", "@", "@", "_acme-challenge", "_deno"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exported := exportDNSRecords("foo.example.com", tt.zone, testDNSRecords)
+			names := make([]string, len(exported))
+			for i, r := range exported {
+				names[i] = r.Name
+			}
+			if !reflect.DeepEqual(names, tt.expected) {
+				t.Errorf("names = %q, want %q", names, tt.expected)
+			}
+			if exported[3].Type != "CNAME" || exported[3].FQDN != "_acme-challenge.foo.example.com" {
+				t.Errorf("unexpected record: %+v", exported[3])
+			}
+		})
+	}
+}
+
+func TestDNSZoneFileFragment(t *testing.T) {
+	expected := "foo\tIN\tA\t10.0.0.1\n" +
+		"foo\tIN\tA\t10.0.0.2\n" +
+		"foo\tIN\tAAAA\t2001:db8::1\n" +
+		"_acme-challenge.foo\tIN\tCNAME\tfoo.acme.deno.dev.\n" +
+		"_deno.foo\tIN\tTXT\t\"token\"\n"
+	if got := dnsZoneFileFragment(exportDNSRecords("foo.example.com", "example.com", testDNSRecords), "example.com"); got != expected {
+		t.Errorf("dnsZoneFileFragment() =\n%s\nwant\n%s", got, expected)
+	}
+
+	expected = "foo.example.com.\tIN\tA\t10.0.0.1\n"
+	if got := dnsZoneFileFragment(exportDNSRecords("foo.example.com", "", testDNSRecords[:1]), ""); got != expected {
+		t.Errorf("dnsZoneFileFragment() without zone = %q, want %q", got, expected)
+	}
+}
+
+func TestDNSRecordsByTypeValue(t *testing.T) {
+	m, diags := dnsRecordsByTypeValue(exportDNSRecords("foo.example.com", "example.com", testDNSRecords))
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	counts := map[string]int{}
+	for k, v := range m.Elements() {
+		counts[k] = len(v.(types.List).Elements())
+	}
+	if !reflect.DeepEqual(counts, map[string]int{"A": 2, "AAAA": 1, "CNAME": 1, "TXT": 1}) {
+		t.Errorf("counts = %v", counts)
+	}
+}
+
+func TestDNSRecordsForEachValue(t *testing.T) {
+	m, diags := dnsRecordsForEachValue(exportDNSRecords("foo.example.com", "example.com", testDNSRecords))
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	keys := slices.Sorted(maps.Keys(m.Elements()))
+	expected := []string{"A foo", "A foo 2", "AAAA foo", "CNAME _acme-challenge.foo", "TXT _deno.foo"}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("keys = %q, want %q", keys, expected)
+	}
+
+	content := m.Elements()["A foo 2"].(types.Object).Attributes()["content"].(types.String).ValueString()
+	if content != "10.0.0.2" {
+		t.Errorf("content = %s, want 10.0.0.2", content)
+	}
+}
+
+func TestQuoteTXT(t *testing.T) {
+	for _, tc := range []struct {
+		text     string
+		expected string
+	}{
+		{"token", `"token"`},
+		{`say "hi"`, `"say \"hi\""`},
+		{`a\b`, `"a\\b"`},
+		{"a\tb\n", `"a\009b\010"`},
+		{"café", `"caf\195\169"`},
+	} {
+		if got := quoteTXT(tc.text); got != tc.expected {
+			t.Errorf("quoteTXT(%q) = %s, want %s", tc.text, got, tc.expected)
+		}
+	}
+}
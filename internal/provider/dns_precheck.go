@@ -0,0 +1,240 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"slices"
+	"strings"
+	"terraform-provider-deno/client"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// maxCNAMEChainLength is the number of CNAME records followed from a name
+// before giving up on a chain that loops.
+const maxCNAMEChainLength = 8
+
+// dnsRecordStatus is the outcome of checking a single DNS record.
+type dnsRecordStatus string
+
+const (
+	dnsRecordFound      dnsRecordStatus = "found"
+	dnsRecordWrongValue dnsRecordStatus = "wrong value"
+	dnsRecordMissing    dnsRecordStatus = "missing"
+)
+
+// dnsRecordCheck is the result of resolving an expected DNS record.
+type dnsRecordCheck struct {
+	Type     string
+	Name     string
+	Expected string
+	Actual   []string
+	Status   dnsRecordStatus
+}
+
+func (c dnsRecordCheck) String() string {
+	actual := "(none)"
+	if len(c.Actual) > 0 {
+		actual = strings.Join(c.Actual, ", ")
+	}
+	return fmt.Sprintf("%s %s: %s\n  expected: %s\n  actual:   %s", c.Type, c.Name, c.Status, c.Expected, actual)
+}
+
+// dnsChecker resolves the DNS records of a domain to check whether they match
+// the expected ones before asking Deno Deploy to verify the domain.
+type dnsChecker struct {
+	resolver *net.Resolver
+}
+
+// newDNSChecker returns a dnsChecker that sends queries to the given resolver
+// address in `host:port` form, or uses the system resolver if it is empty.
+func newDNSChecker(address string) *dnsChecker {
+	if address == "" {
+		return &dnsChecker{resolver: net.DefaultResolver}
+	}
+
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	return &dnsChecker{
+		resolver: &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				return dialer.DialContext(ctx, network, address)
+			},
+		},
+	}
+}
+
+// checkRecords resolves each of the expected records of the domain. Records
+// of types that can't be resolved are skipped.
+func (c *dnsChecker) checkRecords(ctx context.Context, domain string, records []client.DnsRecord) ([]dnsRecordCheck, error) {
+	checks := make([]dnsRecordCheck, 0, len(records))
+	for _, record := range records {
+		if !isCheckableDNSRecordType(record.Type) {
+			tflog.Warn(ctx, "Skipping DNS pre-check of unsupported record type", map[string]any{
+				"type": record.Type,
+				"name": record.Name,
+			})
+			continue
+		}
+		check, err := c.checkRecord(ctx, domain, record)
+		if err != nil {
+			return nil, err
+		}
+		tflog.Info(ctx, "Checked DNS record", map[string]any{
+			"type":     check.Type,
+			"name":     check.Name,
+			"status":   string(check.Status),
+			"expected": check.Expected,
+			"actual":   check.Actual,
+		})
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
+
+func isCheckableDNSRecordType(recordType string) bool {
+	switch strings.ToUpper(recordType) {
+	case "A", "AAAA", "CNAME", "TXT":
+		return true
+	}
+	return false
+}
+
+func (c *dnsChecker) checkRecord(ctx context.Context, domain string, record client.DnsRecord) (dnsRecordCheck, error) {
+	check := dnsRecordCheck{
+		Type:     strings.ToUpper(record.Type),
+		Name:     dnsRecordFQDN(domain, record.Name),
+		Expected: record.Content,
+	}
+
+	var (
+		actual []string
+		err    error
+	)
+	switch check.Type {
+	case "A", "AAAA":
+		network := "ip4"
+		if check.Type == "AAAA" {
+			network = "ip6"
+		}
+		var addrs []netip.Addr
+		addrs, err = c.resolver.LookupNetIP(ctx, network, check.Name)
+		for _, addr := range addrs {
+			actual = append(actual, addr.Unmap().String())
+		}
+	case "CNAME":
+		var cname string
+		cname, err = c.resolver.LookupCNAME(ctx, check.Name)
+		// The resolver returns the name itself when there is no CNAME record
+		if err == nil && !strings.EqualFold(strings.TrimSuffix(cname, "."), check.Name) {
+			actual = []string{c.cnameChainValue(ctx, strings.TrimSuffix(cname, "."), record.Content)}
+		}
+	case "TXT":
+		actual, err = c.resolver.LookupTXT(ctx, check.Name)
+	default:
+		return check, fmt.Errorf("unsupported DNS record type %s for %s", record.Type, check.Name)
+	}
+
+	var dnsErr *net.DNSError
+	if err != nil && !(errors.As(err, &dnsErr) && dnsErr.IsNotFound) {
+		return check, fmt.Errorf("could not resolve %s record of %s: %w", check.Type, check.Name, err)
+	}
+
+	check.Actual = actual
+	switch {
+	case len(actual) == 0:
+		check.Status = dnsRecordMissing
+	case slices.ContainsFunc(actual, func(v string) bool { return dnsRecordValueEqual(check.Type, record.Content, v) }):
+		check.Status = dnsRecordFound
+	default:
+		check.Status = dnsRecordWrongValue
+	}
+	return check, nil
+}
+
+// cnameChainValue returns the expected target if the CNAME record pointing to
+// target is as good as one pointing to it, and target otherwise. Depending on
+// the resolver, target is the first hop of the chain of CNAME records or its
+// end, so the record is accepted when the chains from target and from the
+// expected target end at the same name, e.g. when the expected target is an
+// alias itself.
+func (c *dnsChecker) cnameChainValue(ctx context.Context, target, expected string) string {
+	expected = strings.TrimSuffix(expected, ".")
+	if dnsRecordValueEqual("CNAME", expected, target) {
+		return target
+	}
+
+	end, err := c.canonicalName(ctx, target)
+	if err != nil {
+		return target
+	}
+	expectedEnd, err := c.canonicalName(ctx, expected)
+	if err != nil || !dnsRecordValueEqual("CNAME", expectedEnd, end) {
+		return target
+	}
+	return expected
+}
+
+// canonicalName follows the chain of CNAME records from the name and returns
+// the name at its end.
+func (c *dnsChecker) canonicalName(ctx context.Context, name string) (string, error) {
+	for range maxCNAMEChainLength {
+		cname, err := c.resolver.LookupCNAME(ctx, name)
+		if err != nil {
+			return "", err
+		}
+		cname = strings.TrimSuffix(cname, ".")
+		if strings.EqualFold(cname, name) {
+			break
+		}
+		name = cname
+	}
+	return name, nil
+}
+
+// dnsRecordFQDN returns the fully qualified name of a record. Names that are
+// not already under the domain are relative to it, and `@` is the domain
+// itself.
+func dnsRecordFQDN(domain, name string) string {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	switch {
+	case name == "" || name == "@" || name == domain:
+		return domain
+	case strings.HasSuffix(name, "."+domain):
+		return name
+	default:
+		return name + "." + domain
+	}
+}
+
+// dnsRecordValueEqual compares an expected record value with a resolved one.
+func dnsRecordValueEqual(recordType, expected, actual string) bool {
+	switch recordType {
+	case "A", "AAAA":
+		e, err1 := netip.ParseAddr(expected)
+		a, err2 := netip.ParseAddr(actual)
+		return err1 == nil && err2 == nil && e.Unmap() == a.Unmap()
+	case "CNAME":
+		return strings.EqualFold(strings.TrimSuffix(expected, "."), strings.TrimSuffix(actual, "."))
+	case "TXT":
+		return strings.Trim(expected, `"`) == actual
+	}
+	return expected == actual
+}
+
+// dnsRecordChecksDiff describes the records that don't match the expected
+// ones, or returns an empty string if all of them were found.
+func dnsRecordChecksDiff(checks []dnsRecordCheck) string {
+	var lines []string
+	for _, check := range checks {
+		if check.Status != dnsRecordFound {
+			lines = append(lines, check.String())
+		}
+	}
+	return strings.Join(lines, "\n\n")
+}
@@ -0,0 +1,224 @@
+package provider
+
+import (
+	"context"
+	"net"
+	"strings"
+	"terraform-provider-deno/client"
+	"testing"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// dnsStubRecord is a record served by the DNS stub.
+type dnsStubRecord struct {
+	Type    dnsmessage.Type
+	Name    string
+	Content string
+}
+
+// startDNSStub starts a DNS server on a local UDP port that answers queries
+// with the given records, and returns its address.
+func startDNSStub(t *testing.T, records []dnsStubRecord) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start DNS stub: %s", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			var req dnsmessage.Message
+			if err := req.Unpack(buf[:n]); err != nil || len(req.Questions) != 1 {
+				continue
+			}
+			res, err := dnsStubAnswer(req, records)
+			if err != nil {
+				continue
+			}
+			_, _ = conn.WriteTo(res, addr)
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func dnsStubAnswer(req dnsmessage.Message, records []dnsStubRecord) ([]byte, error) {
+	q := req.Questions[0]
+	name := strings.TrimSuffix(strings.ToLower(q.Name.String()), ".")
+
+	res := dnsmessage.Message{
+		Header: dnsmessage.Header{
+			ID:                 req.ID,
+			Response:           true,
+			Authoritative:      true,
+			RecursionDesired:   req.RecursionDesired,
+			RecursionAvailable: true,
+			RCode:              dnsmessage.RCodeNameError,
+		},
+		Questions: req.Questions,
+	}
+
+	// Like a recursive resolver, follow the chain of CNAME records and answer
+	// with all of them
+	for hops := 0; hops < 8; hops++ {
+		var next string
+		for _, record := range records {
+			if record.Name != name {
+				continue
+			}
+			res.RCode = dnsmessage.RCodeSuccess
+			// A CNAME record answers queries of any type
+			if record.Type != q.Type && record.Type != dnsmessage.TypeCNAME {
+				continue
+			}
+
+			header := dnsmessage.ResourceHeader{Name: dnsmessage.MustNewName(name + "."), Type: record.Type, Class: dnsmessage.ClassINET, TTL: 60}
+			var body dnsmessage.ResourceBody
+			switch record.Type {
+			case dnsmessage.TypeA:
+				ip := net.ParseIP(record.Content).To4()
+				body = &dnsmessage.AResource{A: [4]byte(ip)}
+			case dnsmessage.TypeAAAA:
+				ip := net.ParseIP(record.Content).To16()
+				body = &dnsmessage.AAAAResource{AAAA: [16]byte(ip)}
+			case dnsmessage.TypeCNAME:
+				body = &dnsmessage.CNAMEResource{CNAME: dnsmessage.MustNewName(record.Content + ".")}
+				if q.Type != dnsmessage.TypeCNAME {
+					next = record.Content
+				}
+			case dnsmessage.TypeTXT:
+				body = &dnsmessage.TXTResource{TXT: []string{record.Content}}
+			}
+			res.Answers = append(res.Answers, dnsmessage.Resource{Header: header, Body: body})
+		}
+		if next == "" {
+			break
+		}
+		name = next
+	}
+
+	return res.Pack()
+}
+
+func TestDNSChecker_CheckRecords(t *testing.T) {
+	addr := startDNSStub(t, []dnsStubRecord{
+		{Type: dnsmessage.TypeA, Name: "foo.example.com", Content: "10.0.0.1"},
+		{Type: dnsmessage.TypeAAAA, Name: "foo.example.com", Content: "2001:db8::2"},
+		{Type: dnsmessage.TypeCNAME, Name: "_acme-challenge.foo.example.com", Content: "wrong.acme.deno.dev"},
+		{Type: dnsmessage.TypeTXT, Name: "_deno.foo.example.com", Content: "token"},
+	})
+	checker := newDNSChecker(addr)
+
+	records := []client.DnsRecord{
+		{Type: "a", Name: "foo.example.com", Content: "10.0.0.1"},
+		{Type: "aaaa", Name: "@", Content: "2001:db8::1"},
+		{Type: "cname", Name: "_acme-challenge", Content: "foo.acme.deno.dev"},
+		{Type: "txt", Name: "_deno.foo.example.com.", Content: `"token"`},
+		{Type: "txt", Name: "_missing", Content: "token"},
+	}
+	checks, err := checker.checkRecords(context.Background(), "foo.example.com", records)
+	if err != nil {
+		t.Fatalf("checkRecords() returned unexpected error: %s", err)
+	}
+
+	expected := []struct {
+		name   string
+		status dnsRecordStatus
+		actual string
+	}{
+		{name: "foo.example.com", status: dnsRecordFound, actual: "10.0.0.1"},
+		{name: "foo.example.com", status: dnsRecordWrongValue, actual: "2001:db8::2"},
+		{name: "_acme-challenge.foo.example.com", status: dnsRecordWrongValue, actual: "wrong.acme.deno.dev"},
+		{name: "_deno.foo.example.com", status: dnsRecordFound, actual: "token"},
+		{name: "_missing.foo.example.com", status: dnsRecordMissing, actual: ""},
+	}
+	if len(checks) != len(expected) {
+		t.Fatalf("checkRecords() returned %d checks, want %d", len(checks), len(expected))
+	}
+	for i, e := range expected {
+		c := checks[i]
+		if c.Name != e.name || c.Status != e.status || strings.Join(c.Actual, ",") != e.actual {
+			t.Errorf("checks[%d] = %s %s %q, want %s %s %q", i, c.Name, c.Status, c.Actual, e.name, e.status, e.actual)
+		}
+	}
+
+	diff := dnsRecordChecksDiff(checks)
+	for _, want := range []string{
+		"AAAA foo.example.com: wrong value\n  expected: 2001:db8::1\n  actual:   2001:db8::2",
+		"CNAME _acme-challenge.foo.example.com: wrong value",
+		"TXT _missing.foo.example.com: missing\n  expected: token\n  actual:   (none)",
+	} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("diff does not contain %q:\n%s", want, diff)
+		}
+	}
+	if strings.Contains(diff, "A foo.example.com: found") {
+		t.Errorf("diff should not contain the records that were found:\n%s", diff)
+	}
+}
+
+func TestDNSChecker_AllFound(t *testing.T) {
+	addr := startDNSStub(t, []dnsStubRecord{
+		{Type: dnsmessage.TypeA, Name: "foo.example.com", Content: "10.0.0.1"},
+		{Type: dnsmessage.TypeCNAME, Name: "_acme-challenge.foo.example.com", Content: "foo.acme.deno.dev"},
+	})
+	checker := newDNSChecker(addr)
+
+	checks, err := checker.checkRecords(context.Background(), "Foo.Example.com.", []client.DnsRecord{
+		{Type: "A", Name: "@", Content: "10.0.0.1"},
+		{Type: "CNAME", Name: "_acme-challenge.foo.example.com", Content: "foo.acme.deno.dev."},
+	})
+	if err != nil {
+		t.Fatalf("checkRecords() returned unexpected error: %s", err)
+	}
+	if diff := dnsRecordChecksDiff(checks); diff != "" {
+		t.Errorf("dnsRecordChecksDiff() = %q, want empty", diff)
+	}
+}
+
+func TestDNSChecker_SkipsUnsupportedType(t *testing.T) {
+	checker := newDNSChecker(startDNSStub(t, nil))
+
+	checks, err := checker.checkRecords(context.Background(), "foo.example.com", []client.DnsRecord{
+		{Type: "MX", Name: "@", Content: "mail.example.com"},
+	})
+	if err != nil {
+		t.Fatalf("checkRecords() returned unexpected error: %s", err)
+	}
+	if len(checks) != 0 {
+		t.Errorf("checkRecords() = %v, want no checks", checks)
+	}
+}
+
+func TestDNSChecker_ChainedCNAME(t *testing.T) {
+	addr := startDNSStub(t, []dnsStubRecord{
+		{Type: dnsmessage.TypeCNAME, Name: "_acme-challenge.foo.example.com", Content: "alias.example.com"},
+		{Type: dnsmessage.TypeCNAME, Name: "alias.example.com", Content: "foo.acme.deno.dev"},
+		{Type: dnsmessage.TypeCNAME, Name: "_acme-challenge.baz.example.com", Content: "foo.acme.deno.dev"},
+		{Type: dnsmessage.TypeCNAME, Name: "foo.acme.deno.dev", Content: "edge.deno.net"},
+		{Type: dnsmessage.TypeA, Name: "edge.deno.net", Content: "10.0.0.1"},
+		{Type: dnsmessage.TypeCNAME, Name: "_acme-challenge.bar.example.com", Content: "other.example.net"},
+		{Type: dnsmessage.TypeA, Name: "other.example.net", Content: "10.0.0.2"},
+	})
+	checker := newDNSChecker(addr)
+
+	checks, err := checker.checkRecords(context.Background(), "example.com", []client.DnsRecord{
+		{Type: "CNAME", Name: "_acme-challenge.foo", Content: "foo.acme.deno.dev"},
+		{Type: "CNAME", Name: "_acme-challenge.bar", Content: "foo.acme.deno.dev"},
+		{Type: "CNAME", Name: "_acme-challenge.baz", Content: "foo.acme.deno.dev"},
+	})
+	if err != nil {
+		t.Fatalf("checkRecords() returned unexpected error: %s", err)
+	}
+	if len(checks) != 3 || checks[0].Status != dnsRecordFound || checks[1].Status != dnsRecordWrongValue || checks[2].Status != dnsRecordFound {
+		t.Errorf("checkRecords() = %v, want only the second record wrong", checks)
+	}
+}
@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"terraform-provider-deno/client"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// fetchDomainsPage returns a fetchPageFunc for the custom domains of the
+// organization.
+func fetchDomainsPage(c client.ClientWithResponsesInterface, organizationID uuid.UUID) fetchPageFunc[client.Domain] {
+	return func(ctx context.Context, page, limit int) ([]client.Domain, error) {
+		res, err := c.ListDomainsWithResponse(ctx, organizationID, &client.ListDomainsParams{
+			Page:  &page,
+			Limit: &limit,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if client.RespIsError(res) {
+			return nil, fmt.Errorf("%s", client.APIErrorDetail(res.HTTPResponse, res.Body))
+		}
+		if res.JSON200 == nil {
+			return nil, nil
+		}
+		return *res.JSON200, nil
+	}
+}
+
+// listAllDomains returns every custom domain of the organization, following
+// the pagination of the API.
+func listAllDomains(ctx context.Context, c client.ClientWithResponsesInterface, organizationID uuid.UUID) ([]client.Domain, error) {
+	var domains []client.Domain
+	for domain, err := range listPages(ctx, fetchDomainsPage(c, organizationID)) {
+		if err != nil {
+			return nil, err
+		}
+		domains = append(domains, domain)
+	}
+	return domains, nil
+}
+
+// detachDeploymentDomains removes the association between the deployment and
+// the custom domains of the organization that currently point to it, so that
+// destroying the deployment doesn't leave the domains routed to it. The
+// domains under deno.dev can't be detached and are left as is. It returns the
+// detached domains.
+func detachDeploymentDomains(ctx context.Context, c client.ClientWithResponsesInterface, organizationID uuid.UUID, deploymentID string) ([]string, error) {
+	deployment, err := c.GetDeploymentWithResponse(ctx, deploymentID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get deployment %s: %w", deploymentID, err)
+	}
+	if deployment.StatusCode() == http.StatusNotFound {
+		// Nothing can be associated with a deployment that doesn't exist
+		return nil, nil
+	}
+	if client.RespIsError(deployment) {
+		return nil, fmt.Errorf("could not get deployment %s: %s", deploymentID, client.APIErrorDetail(deployment.HTTPResponse, deployment.Body))
+	}
+	if deployment.JSON200.Domains == nil || len(*deployment.JSON200.Domains) == 0 {
+		return nil, nil
+	}
+
+	associated := map[string]bool{}
+	for _, d := range *deployment.JSON200.Domains {
+		associated[strings.ToLower(d)] = true
+	}
+
+	domains, err := listAllDomains(ctx, c, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("could not list the domains of organization %s: %w", organizationID, err)
+	}
+
+	var detached []string
+	for _, domain := range domains {
+		if !associated[strings.ToLower(domain.Domain)] {
+			continue
+		}
+
+		res, err := c.UpdateDomainAssociationWithResponse(ctx, domain.Id, client.UpdateDomainAssociationJSONRequestBody{
+			DeploymentId: nil,
+		})
+		if err != nil {
+			return detached, fmt.Errorf("could not detach domain %s: %w", domain.Domain, err)
+		}
+		if client.RespIsError(res) {
+			return detached, fmt.Errorf("could not detach domain %s: %s", domain.Domain, client.APIErrorDetail(res.HTTPResponse, res.Body))
+		}
+
+		tflog.Info(ctx, "Detached domain from deployment", map[string]any{
+			"domain":        domain.Domain,
+			"deployment_id": deploymentID,
+		})
+		detached = append(detached, domain.Domain)
+	}
+
+	return detached, nil
+}
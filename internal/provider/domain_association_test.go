@@ -0,0 +1,180 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"terraform-provider-deno/client"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// fakeAssociationClient serves a deployment and the domains of an
+// organization, and records the association updates.
+type fakeAssociationClient struct {
+	client.ClientWithResponsesInterface
+
+	deployment *client.Deployment
+	domains    []client.Domain
+	pageSizes  []int
+	updates    map[uuid.UUID]*client.DeploymentId
+}
+
+func (c *fakeAssociationClient) GetDeploymentWithResponse(_ context.Context, _ client.DeploymentId, _ ...client.RequestEditorFn) (*client.GetDeploymentResponse, error) {
+	if c.deployment == nil {
+		return &client.GetDeploymentResponse{HTTPResponse: &http.Response{StatusCode: http.StatusNotFound}}, nil
+	}
+	return &client.GetDeploymentResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+		JSON200:      c.deployment,
+	}, nil
+}
+
+func (c *fakeAssociationClient) ListDomainsWithResponse(_ context.Context, _ uuid.UUID, params *client.ListDomainsParams, _ ...client.RequestEditorFn) (*client.ListDomainsResponse, error) {
+	start := (*params.Page - 1) * *params.Limit
+	end := min(start+*params.Limit, len(c.domains))
+	page := []client.Domain{}
+	if start < len(c.domains) {
+		page = c.domains[start:end]
+	}
+	c.pageSizes = append(c.pageSizes, len(page))
+	return &client.ListDomainsResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+		JSON200:      &page,
+	}, nil
+}
+
+func (c *fakeAssociationClient) UpdateDomainAssociationWithResponse(_ context.Context, domainID uuid.UUID, body client.UpdateDomainAssociationJSONRequestBody, _ ...client.RequestEditorFn) (*client.UpdateDomainAssociationResponse, error) {
+	if c.updates == nil {
+		c.updates = map[uuid.UUID]*client.DeploymentId{}
+	}
+	c.updates[domainID] = body.DeploymentId
+	return &client.UpdateDomainAssociationResponse{HTTPResponse: &http.Response{StatusCode: http.StatusOK}}, nil
+}
+
+func TestDetachDeploymentDomains(t *testing.T) {
+	domains := make([]client.Domain, listPageSize+1)
+	for i := range domains {
+		domains[i] = client.Domain{Id: uuid.New(), Domain: "unrelated-" + uuid.NewString() + ".example.com"}
+	}
+	// One domain on each page is associated with the deployment
+	domains[0].Domain = "foo.example.com"
+	domains[listPageSize].Domain = "bar.example.com"
+
+	fake := &fakeAssociationClient{
+		deployment: &client.Deployment{
+			Id:      "abcdefghijkl",
+			Domains: &[]string{"my-project-abcdefghijkl.deno.dev", "Foo.example.com", "bar.example.com"},
+		},
+		domains: domains,
+	}
+
+	detached, err := detachDeploymentDomains(context.Background(), fake, uuid.New(), "abcdefghijkl")
+	if err != nil {
+		t.Fatalf("detachDeploymentDomains() returned unexpected error: %s", err)
+	}
+
+	if !reflect.DeepEqual(detached, []string{"foo.example.com", "bar.example.com"}) {
+		t.Errorf("detached = %v", detached)
+	}
+	if !reflect.DeepEqual(fake.pageSizes, []int{listPageSize, 1}) {
+		t.Errorf("page sizes = %v, want all the domains to be listed", fake.pageSizes)
+	}
+	if len(fake.updates) != 2 {
+		t.Fatalf("updates = %v, want 2 updates", fake.updates)
+	}
+	for _, id := range []uuid.UUID{domains[0].Id, domains[listPageSize].Id} {
+		deploymentID, ok := fake.updates[id]
+		if !ok || deploymentID != nil {
+			t.Errorf("domain %s should be detached, got %v", id, deploymentID)
+		}
+	}
+}
+
+func TestDetachDeploymentDomains_NoCustomDomain(t *testing.T) {
+	fake := &fakeAssociationClient{
+		deployment: &client.Deployment{
+			Id:      "abcdefghijkl",
+			Domains: &[]string{"my-project-abcdefghijkl.deno.dev"},
+		},
+		domains: []client.Domain{{Id: uuid.New(), Domain: "foo.example.com"}},
+	}
+
+	detached, err := detachDeploymentDomains(context.Background(), fake, uuid.New(), "abcdefghijkl")
+	if err != nil {
+		t.Fatalf("detachDeploymentDomains() returned unexpected error: %s", err)
+	}
+	if len(detached) != 0 || len(fake.updates) != 0 {
+		t.Errorf("detached = %v, updates = %v, want none", detached, fake.updates)
+	}
+}
+
+func TestDetachDeploymentDomains_DeploymentNotFound(t *testing.T) {
+	detached, err := detachDeploymentDomains(context.Background(), &fakeAssociationClient{}, uuid.New(), "abcdefghijkl")
+	if err != nil {
+		t.Fatalf("detachDeploymentDomains() returned unexpected error: %s", err)
+	}
+	if len(detached) != 0 {
+		t.Errorf("detached = %v, want none", detached)
+	}
+}
+
+func TestDomainResourcesDeleteWarn(t *testing.T) {
+	domainID := uuid.NewString()
+
+	t.Run("certificate", func(t *testing.T) {
+		r := &certificateProvisioningResource{}
+		resp := deleteResource(t, r, map[string]any{"domain_id": domainID, "provisioning_status": "success"})
+		if resp.Diagnostics.HasError() || resp.Diagnostics.WarningsCount() != 1 {
+			t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+		}
+		if !strings.Contains(resp.Diagnostics[0].Summary(), domainID) {
+			t.Errorf("warning should name the domain: %s", resp.Diagnostics[0].Summary())
+		}
+	})
+
+	t.Run("verification", func(t *testing.T) {
+		r := &domainVerificationResource{}
+		resp := deleteResource(t, r, map[string]any{"domain_id": domainID, "verified": true})
+		if resp.Diagnostics.HasError() || resp.Diagnostics.WarningsCount() != 1 {
+			t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+		}
+		if !strings.Contains(resp.Diagnostics[0].Summary(), domainID) {
+			t.Errorf("warning should name the domain: %s", resp.Diagnostics[0].Summary())
+		}
+	})
+}
+
+// deleteResource calls Delete on the resource with a state holding the given
+// attribute values, leaving the other attributes null.
+func deleteResource(t *testing.T, r resource.Resource, values map[string]any) *resource.DeleteResponse {
+	t.Helper()
+	ctx := context.Background()
+
+	schemaResp := &resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, schemaResp)
+	objectType, ok := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	if !ok {
+		t.Fatal("schema type is not an object")
+	}
+
+	attrs := map[string]tftypes.Value{}
+	for name, typ := range objectType.AttributeTypes {
+		attrs[name] = tftypes.NewValue(typ, values[name])
+	}
+
+	req := resource.DeleteRequest{
+		State: tfsdk.State{
+			Schema: schemaResp.Schema,
+			Raw:    tftypes.NewValue(objectType, attrs),
+		},
+	}
+	resp := &resource.DeleteResponse{}
+	r.Delete(ctx, req, resp)
+	return resp
+}
@@ -2,11 +2,15 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"terraform-provider-deno/client"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -15,8 +19,11 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource              = &certificateProvisioningResource{}
-	_ resource.ResourceWithConfigure = &certificateProvisioningResource{}
+	_ resource.Resource                   = &certificateProvisioningResource{}
+	_ resource.ResourceWithConfigure      = &certificateProvisioningResource{}
+	_ resource.ResourceWithModifyPlan     = &certificateProvisioningResource{}
+	_ resource.ResourceWithValidateConfig = &certificateProvisioningResource{}
+	_ resource.ResourceWithUpgradeState   = &certificateProvisioningResource{}
 )
 
 // NewCertificateProvisioningResource is a helper function to simplify the provider implementation.
@@ -24,16 +31,57 @@ func NewCertificateProvisioningResource() resource.Resource {
 	return &certificateProvisioningResource{}
 }
 
+// certificateProvisioningSchemaVersion is the version of the
+// deno_domain_certificate schema. See deploymentSchemaVersion on bumping it.
+const certificateProvisioningSchemaVersion = 0
+
 // certificateProvisioningResource is the resource implementation.
 type certificateProvisioningResource struct {
 	client         client.ClientWithResponsesInterface
 	organizationID uuid.UUID
+
+	// poller overrides the default polling of the provisioning status in
+	// tests.
+	poller *poller
 }
 
+const defaultCertificateProvisioningTimeout = 10 * time.Minute
+
 // certificateProvisioningResourceModel maps the resource schema data.
 type certificateProvisioningResourceModel struct {
-	DomainID           types.String `tfsdk:"domain_id"`
-	ProvisioningStatus types.String `tfsdk:"provisioning_status"`
+	DomainID           types.String   `tfsdk:"domain_id"`
+	ProvisioningStatus types.String   `tfsdk:"provisioning_status"`
+	RenewBefore        types.String   `tfsdk:"renew_before"`
+	Timeouts           timeouts.Value `tfsdk:"timeouts"`
+}
+
+// certificateProvisioningStatus is the provisioning status of a domain. Code
+// and Message are only set when the provisioning failed.
+type certificateProvisioningStatus struct {
+	Status  string
+	Code    string
+	Message string
+}
+
+// isTerminal reports whether the provisioning has finished, successfully or
+// not.
+func (s certificateProvisioningStatus) isTerminal() bool {
+	return s.Status != "pending"
+}
+
+// failureDetail describes why the provisioning didn't succeed, or returns an
+// empty string if it did.
+func (s certificateProvisioningStatus) failureDetail() string {
+	switch s.Status {
+	case "success":
+		return ""
+	case "failed":
+		return fmt.Sprintf("Provisioning failed with code %s: %s", s.Code, s.Message)
+	case "manual":
+		return "Provisioning status is manual, which means the certificates of the domain are managed manually and can't be provisioned automatically"
+	default:
+		return fmt.Sprintf("Provisioning status is %s, expected success", s.Status)
+	}
 }
 
 // Metadata returns the resource type name.
@@ -44,9 +92,14 @@ func (r *certificateProvisioningResource) Metadata(_ context.Context, req resour
 // Schema defines the schema for the resource.
 func (r *certificateProvisioningResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version: certificateProvisioningSchemaVersion,
 		Description: `
 A resource for an automatic certificate provisioning of a custom domain.
 
+Destroying this resource doesn't remove the certificates, since Deno Deploy has no API to remove them; they stay attached to the domain until the domain itself is deleted. A warning is shown when that happens.
+
+Applying the resource waits until the provisioning finishes. Issuing a certificate usually takes a few minutes, during which the provisioning status is pending.
+
 In order to assoaicte a custom domain with a deployment, certificates needs to be ready in some way. For more information regarding the setup process, please refer to the doc of deno_domain resource.
 		`,
 		Attributes: map[string]schema.Attribute{
@@ -58,12 +111,135 @@ In order to assoaicte a custom domain with a deployment, certificates needs to b
 				Computed:    true,
 				Description: "The status of the certificate provisioning. Possible values are `success`, `failed`, `pending`, and `manual`.",
 			},
+			"renew_before": schema.StringAttribute{
+				Optional:    true,
+				Description: "How long before the expiry of a certificate to provision the certificates again, such as `720h` for 30 days. When the earliest certificate of the domain expires within this duration, a re-provision is planned with a warning. Defaults to never planning a re-provision.",
+			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+			}),
 		},
 	}
 }
 
+// ValidateConfig validates renew_before at plan time, including on create.
+func (r *certificateProvisioningResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var renewBefore types.String
+	diags := req.Config.GetAttribute(ctx, path.Root("renew_before"), &renewBefore)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || renewBefore.IsNull() || renewBefore.IsUnknown() {
+		return
+	}
+
+	_, diags = parseRenewBefore(renewBefore)
+	resp.Diagnostics.Append(diags...)
+}
+
+// parseRenewBefore parses renew_before, which must be a positive duration.
+func parseRenewBefore(value types.String) (time.Duration, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	renewBefore, err := time.ParseDuration(value.ValueString())
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("renew_before"),
+			"Invalid Renew Before Duration",
+			fmt.Sprintf("Could not parse %q as a duration: %s", value.ValueString(), err.Error()),
+		)
+		return 0, diags
+	}
+	if renewBefore <= 0 {
+		diags.AddAttributeError(
+			path.Root("renew_before"),
+			"Invalid Renew Before Duration",
+			fmt.Sprintf("The duration must be positive, got %s", value.ValueString()),
+		)
+	}
+	return renewBefore, diags
+}
+
+// ModifyPlan plans a re-provision when a certificate of the domain expires
+// within renew_before.
+func (r *certificateProvisioningResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to renew on create or destroy
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan certificateProvisioningResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.RenewBefore.IsNull() || plan.RenewBefore.IsUnknown() || plan.DomainID.IsUnknown() {
+		return
+	}
+
+	renewBefore, diags := parseRenewBefore(plan.RenewBefore)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	domainID, err := uuid.Parse(plan.DomainID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Unable to Plan Certificate Provisioning %s", plan.DomainID),
+			fmt.Sprintf("Could not parse domain ID %s: %s", plan.DomainID, err.Error()),
+		)
+		return
+	}
+
+	domain, err := r.client.GetDomainWithResponse(ctx, domainID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Unable to Plan Certificate Provisioning %s", plan.DomainID),
+			fmt.Sprintf("GetDomain API returned error: %s", err.Error()),
+		)
+		return
+	}
+	if client.RespIsError(domain) {
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Unable to Plan Certificate Provisioning %s", plan.DomainID),
+			client.APIErrorDetail(domain.HTTPResponse, domain.Body),
+		)
+		return
+	}
+
+	expiry, ok := earliestCertificateExpiry(domain.JSON200.Certificates)
+	if !ok || !certificateNeedsRenewal(expiry, time.Now(), renewBefore) {
+		return
+	}
+
+	// Marking the status unknown plans an update, which provisions the
+	// certificates again
+	plan.ProvisioningStatus = types.StringUnknown()
+	diags = resp.Plan.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.AddWarning(
+		fmt.Sprintf("Certificate for Domain %s Is Close to Expiry", domain.JSON200.Domain),
+		fmt.Sprintf("A certificate of %s expires at %s, which is within renew_before (%s). The certificates will be provisioned again.", domain.JSON200.Domain, expiry.Format(time.RFC3339), renewBefore),
+	)
+}
+
+// certificateNeedsRenewal reports whether a certificate expiring at expiry
+// should be renewed at now.
+func certificateNeedsRenewal(expiry, now time.Time, renewBefore time.Duration) bool {
+	return !now.Add(renewBefore).Before(expiry)
+}
+
 // Create creates the resource and sets the initial Terraform state.
 func (r *certificateProvisioningResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := startSpan(ctx, "deno_domain_certificate.Create")
+	defer endSpan(span, &resp.Diagnostics)
+
 	// Retrieve values from plan
 	var plan certificateProvisioningResourceModel
 	diags := req.Plan.Get(ctx, &plan)
@@ -72,6 +248,12 @@ func (r *certificateProvisioningResource) Create(ctx context.Context, req resour
 		return
 	}
 
+	timeout, diags := plan.Timeouts.Create(ctx, defaultCertificateProvisioningTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	domainID, err := uuid.Parse(plan.DomainID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -104,15 +286,15 @@ func (r *certificateProvisioningResource) Create(ctx context.Context, req resour
 		return
 	}
 
-	// Call the API to get the provisioning status
-	provisioningStatus, diag := r.getCurrentProvisioningStatus(ctx, domainID)
-	resp.Diagnostics.Append(diag)
+	// Wait for the provisioning to finish
+	provisioningStatus, diags := r.waitForProvisioning(ctx, domainID, timeout)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	// Set provisioning status to plan
-	plan.ProvisioningStatus = types.StringValue(provisioningStatus)
+	plan.ProvisioningStatus = types.StringValue(provisioningStatus.Status)
 
 	// Set state
 	diags = resp.State.Set(ctx, plan)
@@ -121,10 +303,10 @@ func (r *certificateProvisioningResource) Create(ctx context.Context, req resour
 		return
 	}
 
-	if provisioningStatus != "success" {
+	if detail := provisioningStatus.failureDetail(); detail != "" {
 		resp.Diagnostics.AddError(
 			fmt.Sprintf("Unable to Provision Certificates for Domain %s", domainID),
-			fmt.Sprintf("Provisioning status is %s, expected success", provisioningStatus),
+			detail,
 		)
 		return
 	}
@@ -132,6 +314,9 @@ func (r *certificateProvisioningResource) Create(ctx context.Context, req resour
 
 // Read refreshes the Terraform state with the latest data.
 func (r *certificateProvisioningResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := startSpan(ctx, "deno_domain_certificate.Read")
+	defer endSpan(span, &resp.Diagnostics)
+
 	// Get current state
 	var state certificateProvisioningResourceModel
 	diags := req.State.Get(ctx, &state)
@@ -155,7 +340,7 @@ func (r *certificateProvisioningResource) Read(ctx context.Context, req resource
 		return
 	}
 
-	state.ProvisioningStatus = types.StringValue(provisioningStatus)
+	state.ProvisioningStatus = types.StringValue(provisioningStatus.Status)
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
@@ -167,6 +352,9 @@ func (r *certificateProvisioningResource) Read(ctx context.Context, req resource
 
 // Update updates the resource and sets the updated Terraform state on success.
 func (r *certificateProvisioningResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := startSpan(ctx, "deno_domain_certificate.Update")
+	defer endSpan(span, &resp.Diagnostics)
+
 	// Follow the same procedure as Create
 
 	// Retrieve values from plan
@@ -177,6 +365,12 @@ func (r *certificateProvisioningResource) Update(ctx context.Context, req resour
 		return
 	}
 
+	timeout, diags := plan.Timeouts.Update(ctx, defaultCertificateProvisioningTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	domainID, err := uuid.Parse(plan.DomainID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -204,15 +398,15 @@ func (r *certificateProvisioningResource) Update(ctx context.Context, req resour
 		return
 	}
 
-	// Call the API to get the provisioning status
-	provisioningStatus, diag := r.getCurrentProvisioningStatus(ctx, domainID)
-	resp.Diagnostics.Append(diag)
+	// Wait for the provisioning to finish
+	provisioningStatus, diags := r.waitForProvisioning(ctx, domainID, timeout)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	// Set provisioning status to plan
-	plan.ProvisioningStatus = types.StringValue(provisioningStatus)
+	plan.ProvisioningStatus = types.StringValue(provisioningStatus.Status)
 
 	// Set state
 	diags = resp.State.Set(ctx, plan)
@@ -221,18 +415,40 @@ func (r *certificateProvisioningResource) Update(ctx context.Context, req resour
 		return
 	}
 
-	if provisioningStatus != "success" {
+	if detail := provisioningStatus.failureDetail(); detail != "" {
 		resp.Diagnostics.AddError(
 			fmt.Sprintf("Unable to Provision Certificates for Domain %s", domainID),
-			fmt.Sprintf("Provisioning status is %s, expected success", provisioningStatus),
+			detail,
 		)
 		return
 	}
 }
 
 // Delete deletes the resource and removes the Terraform state on success.
+// Deno Deploy has no API to remove certificates, so this only warns that
+// they are left on the domain.
 func (r *certificateProvisioningResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	// noop
+	ctx, span := startSpan(ctx, "deno_domain_certificate.Delete")
+	defer endSpan(span, &resp.Diagnostics)
+
+	// Retrieve values from state
+	var state certificateProvisioningResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.AddWarning(
+		fmt.Sprintf("Certificates for Domain %s Were Not Removed", state.DomainID.ValueString()),
+		"Deno Deploy has no API to remove the certificates of a domain, so they stay attached to the domain and keep being served. They are removed when the domain is deleted, e.g. by destroying deno_domain resource.",
+	)
+}
+
+// UpgradeState returns the upgraders of the state written by the prior
+// versions of the schema, of which there are none yet.
+func (r *certificateProvisioningResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{}
 }
 
 // Configure adds the provider configured client to the resource.
@@ -252,25 +468,88 @@ func (r *certificateProvisioningResource) Configure(_ context.Context, req resou
 		return
 	}
 
+	resp.Diagnostics.Append(providerData.connect()...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	r.client = providerData.client
 	r.organizationID = providerData.organizationID
 }
 
-func (r *certificateProvisioningResource) getCurrentProvisioningStatus(ctx context.Context, domainID uuid.UUID) (string, diag.Diagnostic) {
+// waitForProvisioning polls the provisioning status of the domain until the
+// provisioning finishes or the timeout expires.
+func (r *certificateProvisioningResource) waitForProvisioning(ctx context.Context, domainID uuid.UUID, timeout time.Duration) (certificateProvisioningStatus, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var (
+		status  certificateProvisioningStatus
+		apiDiag diag.Diagnostic
+	)
+	p := r.poller
+	if p == nil {
+		p = &poller{
+			Interval:    defaultPollInterval,
+			MaxInterval: defaultMaxPollInterval,
+			Multiplier:  defaultBackoffMultiplier,
+			Jitter:      pollJitter,
+		}
+	}
+	err := p.poll(ctx, func(ctx context.Context, attempt int) (bool, error) {
+		// Call the API to get the provisioning status
+		status, apiDiag = r.getCurrentProvisioningStatus(ctx, domainID)
+		if apiDiag != nil {
+			return false, errCertificateProvisioningAPI
+		}
+		tflog.Info(ctx, "Certificate provisioning status", map[string]any{
+			"domain_id": domainID.String(),
+			"attempt":   attempt,
+			"status":    status.Status,
+		})
+		reportProgress(ctx, "Certificate provisioning status of domain %s is %s (attempt %d)", domainID, status.Status, attempt)
+		return status.isTerminal(), nil
+	})
+	switch {
+	case err == nil:
+	case errors.Is(err, errCertificateProvisioningAPI):
+		diags.Append(apiDiag)
+	case errors.Is(err, context.DeadlineExceeded):
+		diags.AddError(
+			fmt.Sprintf("Unable to Provision Certificates for Domain %s", domainID),
+			fmt.Sprintf("Timed out after %s, provisioning status is still %s", timeout, status.Status),
+		)
+	default:
+		diags.AddError(
+			fmt.Sprintf("Unable to Provision Certificates for Domain %s", domainID),
+			err.Error(),
+		)
+	}
+
+	return status, diags
+}
+
+// errCertificateProvisioningAPI stops polling when the API returns an error,
+// which is then reported as is.
+var errCertificateProvisioningAPI = errors.New("failed to get provisioning status")
+
+func (r *certificateProvisioningResource) getCurrentProvisioningStatus(ctx context.Context, domainID uuid.UUID) (certificateProvisioningStatus, diag.Diagnostic) {
 	domain, err := r.client.GetDomainWithResponse(ctx, domainID)
 	if err != nil {
 		d := diag.NewErrorDiagnostic(
 			fmt.Sprintf("Failed to Get Domain Info for Domain %s", domainID),
 			fmt.Sprintf("GetDomain API returned error: %s", err.Error()),
 		)
-		return "", d
+		return certificateProvisioningStatus{}, d
 	}
 	if client.RespIsError(domain) {
 		d := diag.NewErrorDiagnostic(
 			fmt.Sprintf("Failed to Get Domain Info for Domain %s", domainID),
 			client.APIErrorDetail(domain.HTTPResponse, domain.Body),
 		)
-		return "", d
+		return certificateProvisioningStatus{}, d
 	}
 
 	status, err := domain.JSON200.ProvisioningStatus.ValueByDiscriminator()
@@ -279,20 +558,22 @@ func (r *certificateProvisioningResource) getCurrentProvisioningStatus(ctx conte
 			"Failed to Get Provisioning Status",
 			err.Error(),
 		)
-		return "", d
+		return certificateProvisioningStatus{}, d
 	}
 
 	// Convert provisioning status to string representation
-	ret := "unknown"
-	switch status.(type) {
+	ret := certificateProvisioningStatus{Status: "unknown"}
+	switch s := status.(type) {
 	case client.ProvisioningStatusSuccess:
-		ret = "success"
+		ret.Status = "success"
 	case client.ProvisioningStatusFailed:
-		ret = "failed"
+		ret.Status = "failed"
+		ret.Code = string(s.Code)
+		ret.Message = s.Message
 	case client.ProvisioningStatusPending:
-		ret = "pending"
+		ret.Status = "pending"
 	case client.ProvisioningStatusManual:
-		ret = "manual"
+		ret.Status = "manual"
 	}
 
 	return ret, nil
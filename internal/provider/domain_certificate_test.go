@@ -0,0 +1,199 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"terraform-provider-deno/client"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// fakeProvisioningClient returns the given provisioning statuses from
+// successive GetDomain calls, repeating the last one.
+type fakeProvisioningClient struct {
+	client.ClientWithResponsesInterface
+
+	statuses []client.ProvisioningStatus
+	calls    int
+}
+
+func (c *fakeProvisioningClient) GetDomainWithResponse(_ context.Context, _ uuid.UUID, _ ...client.RequestEditorFn) (*client.GetDomainResponse, error) {
+	status := c.statuses[min(c.calls, len(c.statuses)-1)]
+	c.calls++
+	return &client.GetDomainResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+		JSON200:      &client.Domain{ProvisioningStatus: status},
+	}, nil
+}
+
+func provisioningStatus(t *testing.T, code string, message string) client.ProvisioningStatus {
+	t.Helper()
+
+	var s client.ProvisioningStatus
+	var err error
+	switch code {
+	case "success":
+		err = s.FromProvisioningStatusSuccess(client.ProvisioningStatusSuccess{Code: "success"})
+	case "pending":
+		err = s.FromProvisioningStatusPending(client.ProvisioningStatusPending{Code: "pending"})
+	case "manual":
+		err = s.FromProvisioningStatusManual(client.ProvisioningStatusManual{Code: "manual"})
+	case "failed":
+		err = s.FromProvisioningStatusFailed(client.ProvisioningStatusFailed{Code: "failed", Message: message})
+	}
+	if err != nil {
+		t.Fatalf("failed to build provisioning status: %s", err)
+	}
+	return s
+}
+
+func TestCertificateProvisioning_WaitForProvisioning(t *testing.T) {
+	tests := []struct {
+		name           string
+		statuses       []string
+		expectedStatus string
+		expectedCalls  int
+		expectedDetail string
+	}{
+		{
+			name:           "pending then success",
+			statuses:       []string{"pending", "pending", "success"},
+			expectedStatus: "success",
+			expectedCalls:  3,
+		},
+		{
+			name:           "pending then failed",
+			statuses:       []string{"pending", "failed"},
+			expectedStatus: "failed",
+			expectedCalls:  2,
+			expectedDetail: "Provisioning failed with code failed: rate limited by the CA",
+		},
+		{
+			name:           "manual",
+			statuses:       []string{"manual"},
+			expectedStatus: "manual",
+			expectedCalls:  1,
+			expectedDetail: "Provisioning status is manual",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeProvisioningClient{}
+			for _, s := range tt.statuses {
+				fake.statuses = append(fake.statuses, provisioningStatus(t, s, "rate limited by the CA"))
+			}
+			r := &certificateProvisioningResource{
+				client: fake,
+				poller: &poller{Interval: time.Second, clock: &fakeClock{}},
+			}
+
+			status, diags := r.waitForProvisioning(context.Background(), uuid.New(), time.Minute)
+			if diags.HasError() {
+				t.Fatalf("waitForProvisioning() returned unexpected diagnostics: %v", diags)
+			}
+			if status.Status != tt.expectedStatus {
+				t.Errorf("status = %s, want %s", status.Status, tt.expectedStatus)
+			}
+			if fake.calls != tt.expectedCalls {
+				t.Errorf("calls = %d, want %d", fake.calls, tt.expectedCalls)
+			}
+			if detail := status.failureDetail(); !strings.HasPrefix(detail, tt.expectedDetail) || (tt.expectedDetail == "") != (detail == "") {
+				t.Errorf("failureDetail() = %q, want %q", detail, tt.expectedDetail)
+			}
+		})
+	}
+}
+
+func TestCertificateProvisioning_WaitForProvisioningTimeout(t *testing.T) {
+	fake := &fakeProvisioningClient{statuses: []client.ProvisioningStatus{provisioningStatus(t, "pending", "")}}
+	r := &certificateProvisioningResource{
+		client: fake,
+		poller: &poller{Interval: time.Millisecond},
+	}
+
+	status, diags := r.waitForProvisioning(context.Background(), uuid.New(), 20*time.Millisecond)
+	if !diags.HasError() {
+		t.Fatal("waitForProvisioning() should time out")
+	}
+	if status.Status != "pending" {
+		t.Errorf("status = %s, want pending", status.Status)
+	}
+	if !strings.Contains(diags[0].Detail(), "provisioning status is still pending") {
+		t.Errorf("unexpected detail: %s", diags[0].Detail())
+	}
+}
+
+func TestEarliestCertificateExpiry(t *testing.T) {
+	if _, ok := earliestCertificateExpiry(nil); ok {
+		t.Error("earliestCertificateExpiry() should return false without certificates")
+	}
+	if v := earliestCertificateExpiryValue(nil); !v.IsNull() {
+		t.Errorf("earliestCertificateExpiryValue() = %s, want null", v)
+	}
+
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	certificates := []client.DomainCertificate{
+		{Cipher: "rsa", ExpiresAt: base.Add(90 * 24 * time.Hour)},
+		{Cipher: "ec", ExpiresAt: base.Add(30 * 24 * time.Hour)},
+		{Cipher: "rsa", ExpiresAt: base.Add(60 * 24 * time.Hour)},
+	}
+	earliest, ok := earliestCertificateExpiry(certificates)
+	if !ok || !earliest.Equal(base.Add(30*24*time.Hour)) {
+		t.Errorf("earliestCertificateExpiry() = %s, %t", earliest, ok)
+	}
+	if v := earliestCertificateExpiryValue(certificates).ValueString(); v != "2026-01-31T00:00:00Z" {
+		t.Errorf("earliestCertificateExpiryValue() = %s", v)
+	}
+}
+
+func TestCertificateNeedsRenewal(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	renewBefore := 30 * 24 * time.Hour
+
+	tests := []struct {
+		name     string
+		expiry   time.Time
+		expected bool
+	}{
+		{name: "far from expiry", expiry: now.Add(31 * 24 * time.Hour), expected: false},
+		{name: "exactly at the threshold", expiry: now.Add(renewBefore), expected: true},
+		{name: "within the threshold", expiry: now.Add(24 * time.Hour), expected: true},
+		{name: "already expired", expiry: now.Add(-time.Hour), expected: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := certificateNeedsRenewal(tt.expiry, now, renewBefore); got != tt.expected {
+				t.Errorf("certificateNeedsRenewal() = %t, want %t", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseRenewBefore(t *testing.T) {
+	tests := []struct {
+		value     string
+		expected  time.Duration
+		expectErr bool
+	}{
+		{value: "720h", expected: 720 * time.Hour},
+		{value: "30days", expectErr: true},
+		{value: "-1h", expectErr: true},
+		{value: "0s", expectErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, diags := parseRenewBefore(types.StringValue(tt.value))
+			if diags.HasError() != tt.expectErr {
+				t.Fatalf("parseRenewBefore() diagnostics = %v, expectErr %t", diags, tt.expectErr)
+			}
+			if !tt.expectErr && got != tt.expected {
+				t.Errorf("parseRenewBefore() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"terraform-provider-deno/client"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ list.ListResource              = &domainListResource{}
+	_ list.ListResourceWithConfigure = &domainListResource{}
+)
+
+// NewDomainListResource is a helper function to simplify the provider implementation.
+func NewDomainListResource() list.ListResource {
+	return &domainListResource{}
+}
+
+// domainListResource lists the custom domains of the organization.
+type domainListResource struct {
+	client         client.ClientWithResponsesInterface
+	organizationID uuid.UUID
+}
+
+// domainListResourceModel maps the list resource schema data.
+type domainListResourceModel struct {
+	DomainSuffix types.String `tfsdk:"domain_suffix"`
+	ProjectID    types.String `tfsdk:"project_id"`
+	Validated    types.Bool   `tfsdk:"validated"`
+}
+
+// matches reports whether the domain passes the filters.
+func (m domainListResourceModel) matches(domain client.Domain) bool {
+	if !m.DomainSuffix.IsNull() {
+		name := hostnameASCII(domain.Domain)
+		suffix := hostnameASCII(m.DomainSuffix.ValueString())
+		if name != suffix && !strings.HasSuffix(name, "."+suffix) {
+			return false
+		}
+	}
+	if !m.ProjectID.IsNull() {
+		if domain.ProjectId == nil || !strings.EqualFold(domain.ProjectId.String(), m.ProjectID.ValueString()) {
+			return false
+		}
+	}
+	if !m.Validated.IsNull() && domain.IsValidated != m.Validated.ValueBool() {
+		return false
+	}
+	return true
+}
+
+// Metadata returns the resource type name.
+func (r *domainListResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_domain"
+}
+
+// ListResourceConfigSchema defines the filters of the list resource.
+func (r *domainListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the custom domains of the organization.",
+		Attributes: map[string]schema.Attribute{
+			"domain_suffix": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only list the domains that are equal to or under this domain, e.g. `example.com` matches both `example.com` and `www.example.com`.",
+			},
+			"project_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only list the domains associated with a deployment of this project.",
+			},
+			"validated": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Only list the domains whose ownership is (`true`) or is not (`false`) verified.",
+			},
+		},
+	}
+}
+
+// List streams the domains matching the filters. The `zone` of the listed
+// domains is not set, so the DNS record exports have fully qualified names.
+func (r *domainListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var config domainListResourceModel
+	diags := req.Config.Get(ctx, &config)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	stream.Results = func(push func(list.ListResult) bool) {
+		var count int64
+		for domain, err := range listPages(ctx, fetchDomainsPage(r.client, r.organizationID)) {
+			if err != nil {
+				var diags diag.Diagnostics
+				diags.AddError("Unable to List Domains", err.Error())
+				push(list.ListResult{Diagnostics: diags})
+				return
+			}
+			if !config.matches(domain) {
+				continue
+			}
+
+			result := req.NewListResult(ctx)
+			result.DisplayName = domain.Domain
+			result.Diagnostics.Append(result.Identity.Set(ctx, newDomainResourceIdentity(&domain))...)
+			if req.IncludeResource {
+				var model domainResourceModel
+				result.Diagnostics.Append(model.setDomain(&domain)...)
+				result.Diagnostics.Append(result.Resource.Set(ctx, model)...)
+			}
+
+			if !push(result) {
+				return
+			}
+			count++
+			if req.Limit > 0 && count >= req.Limit {
+				return
+			}
+		}
+	}
+}
+
+// Configure adds the provider configured client to the list resource.
+func (r *domainListResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*deployProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected List Resource Configure Type",
+			fmt.Sprintf("Expected *deployProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	resp.Diagnostics.Append(providerData.connect()...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.client = providerData.client
+	r.organizationID = providerData.organizationID
+}
@@ -0,0 +1,117 @@
+package provider
+
+import (
+	"context"
+	"terraform-provider-deno/client"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestDomainListResource(t *testing.T) {
+	organizationID := uuid.New()
+	projectID := uuid.New()
+	fake := &fakeListClient{
+		domains: []client.Domain{
+			{Id: uuid.New(), OrganizationId: organizationID, Domain: "example.com", IsValidated: true, ProjectId: &projectID},
+			{Id: uuid.New(), OrganizationId: organizationID, Domain: "www.example.com", IsValidated: false, ProjectId: &projectID},
+			{Id: uuid.New(), OrganizationId: organizationID, Domain: "münchen.example.com", IsValidated: true},
+			{Id: uuid.New(), OrganizationId: organizationID, Domain: "notexample.com", IsValidated: true, ProjectId: &projectID},
+			{
+				Id:             uuid.New(),
+				OrganizationId: organizationID,
+				Domain:         "api.example.com",
+				IsValidated:    true,
+				ProjectId:      &projectID,
+				DnsRecords:     []client.DnsRecord{{Type: "A", Name: "api", Content: "10.0.0.1"}},
+			},
+		},
+	}
+	lr := &domainListResource{client: fake, organizationID: organizationID}
+
+	tests := []struct {
+		name     string
+		config   map[string]any
+		expected []string
+	}{
+		{
+			name:     "no filter",
+			expected: []string{"example.com", "www.example.com", "münchen.example.com", "notexample.com", "api.example.com"},
+		},
+		{
+			name:     "domain suffix",
+			config:   map[string]any{"domain_suffix": "Example.com."},
+			expected: []string{"example.com", "www.example.com", "münchen.example.com", "api.example.com"},
+		},
+		{
+			name:     "project and validated",
+			config:   map[string]any{"project_id": projectID.String(), "validated": true},
+			expected: []string{"example.com", "notexample.com", "api.example.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, diags := runList(t, lr, &domainResource{}, tt.config, false, 0)
+			if diags.HasError() {
+				t.Fatalf("List() returned unexpected diagnostics: %v", diags)
+			}
+			var got []string
+			for _, result := range results {
+				got = append(got, result.DisplayName)
+			}
+			if len(got) != len(tt.expected) {
+				t.Fatalf("List() = %v, want %v", got, tt.expected)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Errorf("List() = %v, want %v", got, tt.expected)
+				}
+			}
+		})
+	}
+}
+
+func TestDomainListResource_IncludeResource(t *testing.T) {
+	organizationID := uuid.New()
+	domainID := uuid.New()
+	fake := &fakeListClient{
+		domains: []client.Domain{{
+			Id:             domainID,
+			OrganizationId: organizationID,
+			Domain:         "münchen.example.com",
+			DnsRecords:     []client.DnsRecord{{Type: "TXT", Name: "_deno", Content: "token"}},
+		}},
+	}
+	lr := &domainListResource{client: fake, organizationID: uuid.New()}
+
+	results, diags := runList(t, lr, &domainResource{}, nil, true, 0)
+	if diags.HasError() {
+		t.Fatalf("List() returned unexpected diagnostics: %v", diags)
+	}
+	if len(results) != 1 {
+		t.Fatalf("List() returned %d results, want 1", len(results))
+	}
+
+	var identity domainResourceIdentityModel
+	if diags := results[0].Identity.Get(context.Background(), &identity); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if identity.OrganizationID.ValueString() != organizationID.String() || identity.Domain.ValueString() != "xn--mnchen-3ya.example.com" {
+		t.Errorf("identity = %v", identity)
+	}
+
+	var state domainResourceModel
+	if diags := results[0].Resource.Get(context.Background(), &state); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if state.ID.ValueString() != domainID.String() || state.DomainASCII.ValueString() != "xn--mnchen-3ya.example.com" {
+		t.Errorf("state = %v", state)
+	}
+	if !state.Zone.IsNull() {
+		t.Errorf("zone = %s, want null", state.Zone)
+	}
+	if expected := "_deno.xn--mnchen-3ya.example.com.\tIN\tTXT\t\"token\"\n"; state.DNSRecordsZoneFile.ValueString() != expected {
+		t.Errorf("zone file = %q, want %q", state.DNSRecordsZoneFile.ValueString(), expected)
+	}
+}
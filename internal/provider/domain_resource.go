@@ -3,14 +3,18 @@ package provider
 import (
 	"context"
 	"fmt"
+	"strings"
 	"terraform-provider-deno/client"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -18,8 +22,13 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource              = &domainResource{}
-	_ resource.ResourceWithConfigure = &domainResource{}
+	_ resource.Resource                   = &domainResource{}
+	_ resource.ResourceWithConfigure      = &domainResource{}
+	_ resource.ResourceWithModifyPlan     = &domainResource{}
+	_ resource.ResourceWithValidateConfig = &domainResource{}
+	_ resource.ResourceWithIdentity       = &domainResource{}
+	_ resource.ResourceWithImportState    = &domainResource{}
+	_ resource.ResourceWithUpgradeState   = &domainResource{}
 )
 
 // NewDomainResource is a helper function to simplify the provider implementation.
@@ -27,6 +36,10 @@ func NewDomainResource() resource.Resource {
 	return &domainResource{}
 }
 
+// domainSchemaVersion is the version of the deno_domain schema. See
+// deploymentSchemaVersion on bumping it.
+const domainSchemaVersion = 0
+
 // domainResource is the resource implementation.
 type domainResource struct {
 	client         client.ClientWithResponsesInterface
@@ -35,12 +48,37 @@ type domainResource struct {
 
 // domainResourceModel maps the resource schema data.
 type domainResourceModel struct {
-	ID         types.String `tfsdk:"id"`
-	Domain     types.String `tfsdk:"domain"`
-	Token      types.String `tfsdk:"token"`
-	DNSRecords types.List   `tfsdk:"dns_records"`
-	CreatedAt  types.String `tfsdk:"created_at"`
-	UpdatedAt  types.String `tfsdk:"updated_at"`
+	ID          types.String  `tfsdk:"id"`
+	Domain      hostnameValue `tfsdk:"domain"`
+	DomainASCII types.String  `tfsdk:"domain_ascii"`
+	Token       types.String  `tfsdk:"token"`
+	DNSRecords  types.List    `tfsdk:"dns_records"`
+
+	Certificates              types.List   `tfsdk:"certificates"`
+	EarliestCertificateExpiry types.String `tfsdk:"earliest_certificate_expiry"`
+
+	Zone               hostnameValue `tfsdk:"zone"`
+	DNSRecordsZoneFile types.String  `tfsdk:"dns_records_zone_file"`
+	DNSRecordsByType   types.Map     `tfsdk:"dns_records_by_type"`
+	DNSRecordsForEach  types.Map     `tfsdk:"dns_records_for_each"`
+
+	CreatedAt types.String `tfsdk:"created_at"`
+	UpdatedAt types.String `tfsdk:"updated_at"`
+}
+
+// domainResourceIdentityModel maps the resource identity schema data. The
+// domain is identified by its name rather than its ID, since the name is
+// unique within an organization.
+type domainResourceIdentityModel struct {
+	OrganizationID types.String `tfsdk:"organization_id"`
+	Domain         types.String `tfsdk:"domain"`
+}
+
+func newDomainResourceIdentity(domain *client.Domain) domainResourceIdentityModel {
+	return domainResourceIdentityModel{
+		OrganizationID: types.StringValue(domain.OrganizationId.String()),
+		Domain:         types.StringValue(hostnameASCII(domain.Domain)),
+	}
 }
 
 // Metadata returns the resource type name.
@@ -51,6 +89,7 @@ func (r *domainResource) Metadata(_ context.Context, req resource.MetadataReques
 // Schema defines the schema for the resource.
 func (r *domainResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version: domainSchemaVersion,
 		Description: `
 A resource for a custom domain.
 
@@ -66,15 +105,36 @@ In order to associate a custom domain with a deployment, you need to verify the
 				Description: "The ID of the domain.",
 			},
 			"domain": schema.StringAttribute{
-				Required:    true,
-				Description: "The custom domain, such as `foo.example.com`",
+				Required:   true,
+				CustomType: hostnameType{},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIf(
+						requiresReplaceIfHostnameChanged,
+						"Changing the domain forces a new domain to be created, unless the new value only differs in case, a trailing dot or IDN encoding.",
+						"Changing the domain forces a new domain to be created, unless the new value only differs in case, a trailing dot or IDN encoding.",
+					),
+				},
+				Description: "The custom domain, such as `foo.example.com`. Internationalized domain names are accepted and converted to punycode, the letters are lowercased and a trailing dot is removed, so `Example.COM.` is equivalent to `example.com`. A wildcard is only allowed as the whole leftmost label, such as `*.example.com`. Changing this forces a new domain to be created, with a new ID, token and DNS records.",
+			},
+			"domain_ascii": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					hostnameASCIIPlanModifier{hostnamePath: path.Root("domain")},
+				},
+				Description: "The normalized ASCII form of the domain, with IDN labels converted to punycode, such as `xn--mnchen-3ya.example.com` for `München.example.com`.",
 			},
 			"token": schema.StringAttribute{
-				Computed:    true,
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 				Description: "The token used for verifying the ownership of the domain.",
 			},
 			"dns_records": schema.ListNestedAttribute{
-				Computed:    true,
+				Computed: true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
 				Description: "The DNS records that need to be added to the DNS nameserver.",
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
@@ -93,6 +153,56 @@ In order to associate a custom domain with a deployment, you need to verify the
 					},
 				},
 			},
+			"zone": schema.StringAttribute{
+				Optional:    true,
+				CustomType:  hostnameType{},
+				Description: "The apex of the DNS zone that the records are added to, such as `example.com`. It must be the domain itself or one of its parents. The names in `dns_records_zone_file`, `dns_records_by_type` and `dns_records_for_each` are relative to it. If omitted, the names are fully qualified.",
+			},
+			"dns_records_zone_file": schema.StringAttribute{
+				Computed:    true,
+				Description: "The DNS records rendered as an RFC 1035 zone file fragment, one resource record per line. The TTL is omitted so that the `$TTL` of the zone file applies. Owner names are relative to `zone`, or absolute if it is omitted.",
+			},
+			"dns_records_by_type": schema.MapAttribute{
+				Computed: true,
+				ElementType: types.ListType{
+					ElemType: types.ObjectType{AttrTypes: dnsExportRecordAttrTypes},
+				},
+				Description: "The DNS records grouped by record type such as `A` or `CNAME`. Each record has `type`, `name` relative to `zone`, fully qualified `fqdn` and `content` attributes.",
+			},
+			"dns_records_for_each": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.ObjectType{AttrTypes: dnsExportRecordAttrTypes},
+				Description: "The DNS records keyed by `<type> <name>`, such as `CNAME _acme-challenge.foo`, ready to be used in `for_each` of the records of any DNS provider. Each record has the same attributes as in `dns_records_by_type`. The records are only known once the domain is created, so the first apply needs to create the domain beforehand, e.g. with `-target`.",
+			},
+			"certificates": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "The TLS certificates of the domain. Certificates are provisioned by deno_domain_certificate resource, and refreshed with the domain.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"cipher": schema.StringAttribute{
+							Computed:    true,
+							Description: "The cipher of the certificate, such as `rsa` or `ec`.",
+						},
+						"created_at": schema.StringAttribute{
+							Computed:    true,
+							Description: "The time the certificate was created, formatted in RFC3339.",
+						},
+						"expires_at": schema.StringAttribute{
+							Computed:    true,
+							Description: "The time the certificate expires, formatted in RFC3339.",
+						},
+						"updated_at": schema.StringAttribute{
+							Computed:    true,
+							Description: "The time the certificate was last updated, formatted in RFC3339.",
+						},
+					},
+				},
+			},
+			"earliest_certificate_expiry": schema.StringAttribute{
+				Computed:            true,
+				Description:         "The time the first of the certificates expires, formatted in RFC3339. Null if the domain has no certificates.",
+				MarkdownDescription: "The time the first of the certificates expires, formatted in [RFC3339](https://datatracker.ietf.org/doc/html/rfc3339). Null if the domain has no certificates.",
+			},
 			"created_at": schema.StringAttribute{
 				Computed: true,
 				PlanModifiers: []planmodifier.String{
@@ -110,8 +220,27 @@ In order to associate a custom domain with a deployment, you need to verify the
 	}
 }
 
+// IdentitySchema defines the identity of the resource.
+func (r *domainResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"organization_id": identityschema.StringAttribute{
+				OptionalForImport: true,
+				Description:       "The ID of the organization that the domain belongs to. Defaults to the organization of the provider.",
+			},
+			"domain": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The domain name, in its ASCII (punycode) form.",
+			},
+		},
+	}
+}
+
 // Create creates the resource and sets the initial Terraform state.
 func (r *domainResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := startSpan(ctx, "deno_domain.Create")
+	defer endSpan(span, &resp.Diagnostics)
+
 	// Retrieve values from plan
 	var plan domainResourceModel
 	diags := req.Plan.Get(ctx, &plan)
@@ -122,7 +251,7 @@ func (r *domainResource) Create(ctx context.Context, req resource.CreateRequest,
 
 	// Call "create domain" API
 	domain, err := r.client.CreateDomainWithResponse(ctx, r.organizationID, client.CreateDomainJSONRequestBody{
-		Domain: plan.Domain.ValueString(),
+		Domain: plan.DomainASCII.ValueString(),
 	})
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -140,18 +269,11 @@ func (r *domainResource) Create(ctx context.Context, req resource.CreateRequest,
 	}
 
 	// Map response body to schema and populate Computed attribute values
-	plan.ID = types.StringValue(domain.JSON200.Id.String())
-	plan.Domain = types.StringValue(domain.JSON200.Domain)
-	plan.Token = types.StringValue(domain.JSON200.Token)
-	plan.CreatedAt = types.StringValue(domain.JSON200.CreatedAt.Format(time.RFC3339))
-	plan.UpdatedAt = types.StringValue(domain.JSON200.UpdatedAt.Format(time.RFC3339))
-
-	dnsRecords, diags := convertToDNSRecordsList(domain.JSON200.DnsRecords)
+	diags = plan.setDomain(domain.JSON200)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	plan.DNSRecords = dnsRecords
 
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, plan)
@@ -159,6 +281,9 @@ func (r *domainResource) Create(ctx context.Context, req resource.CreateRequest,
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	diags = resp.Identity.Set(ctx, newDomainResourceIdentity(domain.JSON200))
+	resp.Diagnostics.Append(diags...)
 }
 
 func convertToDNSRecordsList(dnsRecords []client.DnsRecord) (types.List, diag.Diagnostics) {
@@ -193,8 +318,187 @@ func convertToDNSRecordsList(dnsRecords []client.DnsRecord) (types.List, diag.Di
 	return dnsRecordsList, nil
 }
 
+// setDomain maps the domain returned by the API to the model.
+func (m *domainResourceModel) setDomain(domain *client.Domain) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	m.ID = types.StringValue(domain.Id.String())
+	m.Domain = newHostnameValue(domain.Domain)
+	m.DomainASCII = types.StringValue(hostnameASCII(domain.Domain))
+	m.Token = types.StringValue(domain.Token)
+	m.CreatedAt = types.StringValue(domain.CreatedAt.Format(time.RFC3339))
+	m.UpdatedAt = types.StringValue(domain.UpdatedAt.Format(time.RFC3339))
+
+	dnsRecords, d := convertToDNSRecordsList(domain.DnsRecords)
+	diags.Append(d...)
+	m.DNSRecords = dnsRecords
+
+	diags.Append(m.setDNSRecordExports(domain.Domain, domain.DnsRecords)...)
+
+	certificates, d := convertToCertificatesList(domain.Certificates)
+	diags.Append(d...)
+	m.Certificates = certificates
+	m.EarliestCertificateExpiry = earliestCertificateExpiryValue(domain.Certificates)
+
+	return diags
+}
+
+// setDNSRecordExports renders the DNS records of the domain in the export
+// formats, with the names relative to the zone.
+func (m *domainResourceModel) setDNSRecordExports(domain string, records []client.DnsRecord) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	zone := ""
+	if !m.Zone.IsNull() {
+		zone = hostnameASCII(m.Zone.ValueString())
+	}
+	exported := exportDNSRecords(hostnameASCII(domain), zone, records)
+
+	m.DNSRecordsZoneFile = types.StringValue(dnsZoneFileFragment(exported, zone))
+
+	byType, d := dnsRecordsByTypeValue(exported)
+	diags.Append(d...)
+	m.DNSRecordsByType = byType
+
+	forEach, d := dnsRecordsForEachValue(exported)
+	diags.Append(d...)
+	m.DNSRecordsForEach = forEach
+
+	return diags
+}
+
+// ValidateConfig checks that the zone contains the domain.
+func (r *domainResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config domainResourceModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.Zone.IsNull() || config.Zone.IsUnknown() || config.Domain.IsUnknown() {
+		return
+	}
+
+	domain, err := normalizeHostname(config.Domain.ValueString())
+	if err != nil {
+		// Reported by the validation of the domain attribute
+		return
+	}
+	zone, err := normalizeHostname(config.Zone.ValueString())
+	if err != nil {
+		return
+	}
+	if domain != zone && !strings.HasSuffix(domain, "."+zone) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("zone"),
+			"Invalid Zone",
+			fmt.Sprintf("The zone %s must be the domain %s itself or one of its parents", zone, domain),
+		)
+	}
+}
+
+// ModifyPlan renders the DNS records in the export formats at plan time, so
+// that changing the zone shows the new values in the plan.
+func (r *domainResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to render on create, where the records are not known yet, or
+	// on destroy
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan domainResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.DNSRecords.IsUnknown() || plan.DomainASCII.IsUnknown() || plan.Zone.IsUnknown() {
+		return
+	}
+
+	var dnsRecords []struct {
+		Type    string `tfsdk:"type"`
+		Name    string `tfsdk:"name"`
+		Content string `tfsdk:"content"`
+	}
+	diags = plan.DNSRecords.ElementsAs(ctx, &dnsRecords, false)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	records := make([]client.DnsRecord, len(dnsRecords))
+	for i, record := range dnsRecords {
+		records[i] = client.DnsRecord{Type: record.Type, Name: record.Name, Content: record.Content}
+	}
+
+	diags = plan.setDNSRecordExports(plan.DomainASCII.ValueString(), records)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.Plan.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+var certificateAttrTypes = map[string]attr.Type{
+	"cipher":     types.StringType,
+	"created_at": types.StringType,
+	"expires_at": types.StringType,
+	"updated_at": types.StringType,
+}
+
+func convertToCertificatesList(certificates []client.DomainCertificate) (types.List, diag.Diagnostics) {
+	ty := types.ObjectType{
+		AttrTypes: certificateAttrTypes,
+	}
+
+	values := make([]attr.Value, len(certificates))
+	for i, certificate := range certificates {
+		elements := map[string]attr.Value{
+			"cipher":     types.StringValue(string(certificate.Cipher)),
+			"created_at": types.StringValue(certificate.CreatedAt.Format(time.RFC3339)),
+			"expires_at": types.StringValue(certificate.ExpiresAt.Format(time.RFC3339)),
+			"updated_at": types.StringValue(certificate.UpdatedAt.Format(time.RFC3339)),
+		}
+		objectValue, diags := types.ObjectValue(certificateAttrTypes, elements)
+		if diags.HasError() {
+			return types.ListNull(ty), diags
+		}
+		values[i] = objectValue
+	}
+
+	return types.ListValue(ty, values)
+}
+
+// earliestCertificateExpiry returns the time the first of the certificates
+// expires, or false if there are no certificates.
+func earliestCertificateExpiry(certificates []client.DomainCertificate) (time.Time, bool) {
+	var earliest time.Time
+	for i, certificate := range certificates {
+		if i == 0 || certificate.ExpiresAt.Before(earliest) {
+			earliest = certificate.ExpiresAt
+		}
+	}
+	return earliest, len(certificates) > 0
+}
+
+func earliestCertificateExpiryValue(certificates []client.DomainCertificate) types.String {
+	earliest, ok := earliestCertificateExpiry(certificates)
+	if !ok {
+		return types.StringNull()
+	}
+	return types.StringValue(earliest.Format(time.RFC3339))
+}
+
 // Read refreshes the Terraform state with the latest data.
 func (r *domainResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := startSpan(ctx, "deno_domain.Read")
+	defer endSpan(span, &resp.Diagnostics)
+
 	// Get current state
 	var state domainResourceModel
 	diags := req.State.Get(ctx, &state)
@@ -230,18 +534,11 @@ func (r *domainResource) Read(ctx context.Context, req resource.ReadRequest, res
 	}
 
 	// Overwtite state with refreshed values
-	state.ID = types.StringValue(domain.JSON200.Id.String())
-	state.Domain = types.StringValue(domain.JSON200.Domain)
-	state.Token = types.StringValue(domain.JSON200.Token)
-	state.CreatedAt = types.StringValue(domain.JSON200.CreatedAt.Format(time.RFC3339))
-	state.UpdatedAt = types.StringValue(domain.JSON200.UpdatedAt.Format(time.RFC3339))
-
-	dnsRecords, diags := convertToDNSRecordsList(domain.JSON200.DnsRecords)
+	diags = state.setDomain(domain.JSON200)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	state.DNSRecords = dnsRecords
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
@@ -249,11 +546,18 @@ func (r *domainResource) Read(ctx context.Context, req resource.ReadRequest, res
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	diags = resp.Identity.Set(ctx, newDomainResourceIdentity(domain.JSON200))
+	resp.Diagnostics.Append(diags...)
 }
 
 // Update updates the resource and sets the updated Terraform state on success.
-// Internally, this is equivalent to deleting the resource and creating a new one.
+// Changing `domain` forces a replacement, so there is nothing to update in
+// place; this only refreshes the state.
 func (r *domainResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := startSpan(ctx, "deno_domain.Update")
+	defer endSpan(span, &resp.Diagnostics)
+
 	// Retrieve values from plan
 	var plan domainResourceModel
 	diags := req.Plan.Get(ctx, &plan)
@@ -271,66 +575,45 @@ func (r *domainResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
-	// Delete existing domain
-	result, err := r.client.DeleteDomainWithResponse(ctx, domainID)
+	domain, err := r.client.GetDomainWithResponse(ctx, domainID)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			fmt.Sprintf("Unable to Update Domain %s", plan.ID),
-			fmt.Sprintf("Could not delete domain with ID %s: %s", plan.ID, err.Error()),
-		)
-		return
-	}
-	if result.StatusCode() != 200 {
-		resp.Diagnostics.AddError(
-			fmt.Sprintf("Unable to delete Domain %s", plan.ID),
-			client.APIErrorDetail(result.HTTPResponse, result.Body),
-		)
-		return
-	}
-
-	// Create a new domain
-	domain, err := r.client.CreateDomainWithResponse(ctx, r.organizationID, client.CreateDomainJSONRequestBody{
-		Domain: plan.Domain.ValueString(),
-	})
-	if err != nil {
-		resp.Diagnostics.AddError(
-			fmt.Sprintf("Unable to Update Domain %s", plan.Domain.ValueString()),
-			err.Error(),
+			fmt.Sprintf("Could not find domain with ID %s: %s", plan.ID, err.Error()),
 		)
 		return
 	}
 	if client.RespIsError(domain) {
 		resp.Diagnostics.AddError(
-			fmt.Sprintf("Unable to Update Domain %s", plan.Domain.ValueString()),
+			fmt.Sprintf("Unable to Update Domain %s", plan.ID),
 			client.APIErrorDetail(domain.HTTPResponse, domain.Body),
 		)
 		return
 	}
 
 	// Map response body to schema and populate Computed attribute values
-	plan.ID = types.StringValue(domain.JSON200.Id.String())
-	plan.Domain = types.StringValue(domain.JSON200.Domain)
-	plan.Token = types.StringValue(domain.JSON200.Token)
-	plan.CreatedAt = types.StringValue(domain.JSON200.CreatedAt.Format(time.RFC3339))
-	plan.UpdatedAt = types.StringValue(domain.JSON200.UpdatedAt.Format(time.RFC3339))
-
-	dnsRecords, diags := convertToDNSRecordsList(domain.JSON200.DnsRecords)
+	diags = plan.setDomain(domain.JSON200)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	plan.DNSRecords = dnsRecords
 
-	// Set state to fully populated data
+	// Set refreshed state
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	diags = resp.Identity.Set(ctx, newDomainResourceIdentity(domain.JSON200))
+	resp.Diagnostics.Append(diags...)
 }
 
 // Delete deletes the resource and removes the Terraform state on success.
 func (r *domainResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := startSpan(ctx, "deno_domain.Delete")
+	defer endSpan(span, &resp.Diagnostics)
+
 	// Retrieve values from state
 	var state domainResourceModel
 	diags := req.State.Get(ctx, &state)
@@ -366,6 +649,56 @@ func (r *domainResource) Delete(ctx context.Context, req resource.DeleteRequest,
 	}
 }
 
+// ImportState imports the existing resource into Terraform. The import ID is
+// either the ID of the domain or its name, and the identity holds the name.
+func (r *domainResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(checkImportOrganization(ctx, req, r.organizationID)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	name := req.ID
+	if name == "" {
+		var identity domainResourceIdentityModel
+		resp.Diagnostics.Append(req.Identity.Get(ctx, &identity)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		name = identity.Domain.ValueString()
+	}
+
+	if _, err := uuid.Parse(name); err == nil {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), name)...)
+		return
+	}
+
+	domains, err := listAllDomains(ctx, r.client, r.organizationID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Unable to Import Domain %s", name),
+			fmt.Sprintf("Could not list the domains of organization %s: %s", r.organizationID, err.Error()),
+		)
+		return
+	}
+	for _, domain := range domains {
+		if hostnamesEqual(domain.Domain, name) {
+			resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), domain.Id.String())...)
+			return
+		}
+	}
+
+	resp.Diagnostics.AddError(
+		fmt.Sprintf("Unable to Import Domain %s", name),
+		fmt.Sprintf("There is no domain %s in organization %s.", name, r.organizationID),
+	)
+}
+
+// UpgradeState returns the upgraders of the state written by the prior
+// versions of the schema, of which there are none yet.
+func (r *domainResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{}
+}
+
 // Configure adds the provider configured client to the resource.
 func (r *domainResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
@@ -383,6 +716,11 @@ func (r *domainResource) Configure(_ context.Context, req resource.ConfigureRequ
 		return
 	}
 
+	resp.Diagnostics.Append(providerData.connect()...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	r.client = providerData.client
 	r.organizationID = providerData.organizationID
 }
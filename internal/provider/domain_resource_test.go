@@ -0,0 +1,242 @@
+package provider_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+	"github.com/thanhpk/randstr"
+)
+
+func randomDomainName() string {
+	return fmt.Sprintf("%s.example.com", randstr.String(16, letters))
+}
+
+func TestAccDomain_ChangeDomainReplaces(t *testing.T) {
+	domain1 := randomDomainName()
+	domain2 := randomDomainName()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccDomainDestroy(t),
+		Steps: []resource.TestStep{
+			{
+				Config: genConfigWithDomain(domain1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("deno_domain.test", "domain", domain1),
+					resource.TestCheckResourceAttrSet("deno_domain.test", "token"),
+				),
+			},
+			{
+				Config: genConfigWithDomain(domain2),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("deno_domain.test", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("deno_domain.test", "domain", domain2),
+				),
+			},
+			{
+				// Re-applying the same configuration doesn't change anything
+				Config: genConfigWithDomain(domain2),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestAccDomain_NormalizeDomain(t *testing.T) {
+	domain := randomDomainName()
+	denormalized := strings.ToUpper(domain) + "."
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccDomainDestroy(t),
+		Steps: []resource.TestStep{
+			{
+				Config: genConfigWithDomain(denormalized),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("deno_domain.test", "domain", denormalized),
+					resource.TestCheckResourceAttr("deno_domain.test", "domain_ascii", domain),
+				),
+			},
+			{
+				// Refreshing doesn't produce a diff even though the API
+				// returns the normalized domain
+				Config: genConfigWithDomain(denormalized),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+			{
+				// Switching to an equivalent spelling doesn't replace the domain
+				Config: genConfigWithDomain(domain),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("deno_domain.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("deno_domain.test", "domain", domain),
+					resource.TestCheckResourceAttr("deno_domain.test", "domain_ascii", domain),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDomain_InvalidDomain(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      genConfigWithDomain("foo..example.com"),
+				ExpectError: regexp.MustCompile(`Invalid Hostname`),
+			},
+			{
+				Config:      genConfigWithDomain("foo.*.example.com"),
+				ExpectError: regexp.MustCompile(`wildcard is only allowed as the whole leftmost label`),
+			},
+		},
+	})
+}
+
+func TestAccDomain_DNSRecordExports(t *testing.T) {
+	domain := randomDomainName()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccDomainDestroy(t),
+		Steps: []resource.TestStep{
+			{
+				Config: genConfigWithDomainAndZone(domain, "example.com"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("deno_domain.test", "dns_records_zone_file"),
+					resource.TestMatchResourceAttr("deno_domain.test", "dns_records_zone_file", regexp.MustCompile(`(?m)^`+strings.TrimSuffix(domain, ".example.com")+`\tIN\t`)),
+				),
+			},
+			{
+				// Changing the zone updates the exports in place
+				Config: genConfigWithDomainAndZone(domain, domain),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("deno_domain.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("deno_domain.test", "dns_records_zone_file", regexp.MustCompile(`(?m)^@\tIN\t`)),
+				),
+			},
+			{
+				Config:      genConfigWithDomainAndZone(domain, "example.org"),
+				ExpectError: regexp.MustCompile(`Invalid Zone`),
+			},
+		},
+	})
+}
+
+func TestAccDomain_Import(t *testing.T) {
+	domain := randomDomainName()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccDomainDestroy(t),
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_12_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: genConfigWithDomain(domain),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectIdentity("deno_domain.test", map[string]knownvalue.Check{
+						"organization_id": knownvalue.StringExact(os.Getenv("DENO_DEPLOY_ORGANIZATION_ID")),
+						"domain":          knownvalue.StringExact(domain),
+					}),
+				},
+			},
+			{
+				// Import by the domain name
+				Config:            genConfigWithDomain(domain),
+				ResourceName:      "deno_domain.test",
+				ImportState:       true,
+				ImportStateId:     domain,
+				ImportStateVerify: true,
+			},
+			{
+				Config:          genConfigWithDomain(domain),
+				ResourceName:    "deno_domain.test",
+				ImportState:     true,
+				ImportStateKind: resource.ImportBlockWithResourceIdentity,
+			},
+		},
+	})
+}
+
+func genConfigWithDomainAndZone(domain string, zone string) string {
+	return fmt.Sprintf(`
+		resource "deno_domain" "test" {
+			domain = "%s"
+			zone   = "%s"
+		}
+	`, domain, zone)
+}
+
+func genConfigWithDomain(domain string) string {
+	return fmt.Sprintf(`
+		resource "deno_domain" "test" {
+			domain = "%s"
+		}
+	`, domain)
+}
+
+func testAccDomainDestroy(t *testing.T) func(*terraform.State) error {
+	client := getAPIClient(t)
+
+	return func(s *terraform.State) error {
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != "deno_domain" {
+				continue
+			}
+			rawDomainID, ok := rs.Primary.Attributes["id"]
+			if !ok {
+				return fmt.Errorf("deno_domain resource is missing id attribute")
+			}
+			domainID, err := uuid.Parse(rawDomainID)
+			if err != nil {
+				return fmt.Errorf("failed to parse domain id: %s", err)
+			}
+			resp, err := client.GetDomainWithResponse(context.Background(), domainID)
+			if err != nil {
+				return fmt.Errorf("failed to get domain: %s", err)
+			}
+			if resp.JSON404 == nil {
+				return fmt.Errorf("domain still exists: %s", rawDomainID)
+			}
+		}
+
+		return nil
+	}
+}
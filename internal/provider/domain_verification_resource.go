@@ -2,21 +2,26 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"terraform-provider-deno/client"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource              = &domainVerificationResource{}
-	_ resource.ResourceWithConfigure = &domainVerificationResource{}
+	_ resource.Resource                   = &domainVerificationResource{}
+	_ resource.ResourceWithConfigure      = &domainVerificationResource{}
+	_ resource.ResourceWithValidateConfig = &domainVerificationResource{}
+	_ resource.ResourceWithUpgradeState   = &domainVerificationResource{}
 )
 
 // NewDomainVerificationResource is a helper function to simplify the provider implementation.
@@ -24,17 +29,28 @@ func NewDomainVerificationResource() resource.Resource {
 	return &domainVerificationResource{}
 }
 
+// domainVerificationSchemaVersion is the version of the
+// deno_domain_verification schema. See deploymentSchemaVersion on bumping it.
+const domainVerificationSchemaVersion = 0
+
 // domainVerificationResource is the resource implementation.
 type domainVerificationResource struct {
 	client         client.ClientWithResponsesInterface
 	organizationID uuid.UUID
 }
 
+const defaultDomainVerificationTimeout = 10 * time.Minute
+
 // domainVerificationResourceModel maps the resource schema data.
 type domainVerificationResourceModel struct {
-	DomainID types.String   `tfsdk:"domain_id"`
-	Verified types.Bool     `tfsdk:"verified"`
-	Timeouts timeouts.Value `tfsdk:"timeouts"`
+	DomainID          types.String   `tfsdk:"domain_id"`
+	Verified          types.Bool     `tfsdk:"verified"`
+	DNSResolver       types.String   `tfsdk:"dns_resolver"`
+	SkipDNSPrecheck   types.Bool     `tfsdk:"skip_dns_precheck"`
+	PollInterval      types.String   `tfsdk:"poll_interval"`
+	MaxPollInterval   types.String   `tfsdk:"max_poll_interval"`
+	BackoffMultiplier types.Float64  `tfsdk:"backoff_multiplier"`
+	Timeouts          timeouts.Value `tfsdk:"timeouts"`
 }
 
 // Metadata returns the resource type name.
@@ -45,10 +61,15 @@ func (r *domainVerificationResource) Metadata(_ context.Context, req resource.Me
 // Schema defines the schema for the resource.
 func (r *domainVerificationResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version: domainVerificationSchemaVersion,
 		Description: `
 A resource for a ownership verification of a custom domain.
 
 In order to assoaicte a custom domain with a deployment, this process must be completed. For more information regarding the setup process, please refer to the doc of deno_domain resource.
+
+Destroying this resource removes it from the Terraform state, but the domain stays verified since the ownership verification can't be revoked. A warning is shown when that happens. Deleting the domain itself is the way to undo the verification.
+
+Before asking Deno Deploy to verify the domain, the DNS records listed in the dns_records attribute of deno_domain are resolved locally. If any of them is missing or has a wrong value, the apply fails right away with the difference between the expected and actual records, instead of waiting for the timeout.
 		`,
 		Attributes: map[string]schema.Attribute{
 			"domain_id": schema.StringAttribute{
@@ -59,6 +80,26 @@ In order to assoaicte a custom domain with a deployment, this process must be co
 				Computed:    true,
 				Description: "Whether or not the domain has been verified.",
 			},
+			"dns_resolver": schema.StringAttribute{
+				Optional:    true,
+				Description: "The address of the DNS resolver used to check the DNS records before the verification, in `host:port` form such as `1.1.1.1:53`. Defaults to the resolver of the system.",
+			},
+			"skip_dns_precheck": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether to skip checking the DNS records locally before the verification. Defaults to `false`. This is useful when the records are not visible from where Terraform runs, e.g. due to split-horizon DNS.",
+			},
+			"poll_interval": schema.StringAttribute{
+				Optional:    true,
+				Description: "The time to wait after the first verification attempt, such as `10s`. The first attempt is made right away. Defaults to `5s`.",
+			},
+			"max_poll_interval": schema.StringAttribute{
+				Optional:    true,
+				Description: "The maximum time to wait between verification attempts, such as `2m`. Defaults to `1m`, or `poll_interval` if it is longer.",
+			},
+			"backoff_multiplier": schema.Float64Attribute{
+				Optional:    true,
+				Description: "The factor by which the time between verification attempts grows after each attempt, up to `max_poll_interval`. Each wait is randomly shortened or lengthened by up to 10% to spread out requests. Set to `1` for a constant interval. Defaults to `1.5`.",
+			},
 			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
 				Create: true,
 			}),
@@ -66,8 +107,24 @@ In order to assoaicte a custom domain with a deployment, this process must be co
 	}
 }
 
+// ValidateConfig validates the polling attributes at plan time.
+func (r *domainVerificationResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config domainVerificationResourceModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, diags = newPollerFromAttributes(config.PollInterval, config.MaxPollInterval, config.BackoffMultiplier)
+	resp.Diagnostics.Append(diags...)
+}
+
 // Create creates the resource and sets the initial Terraform state.
 func (r *domainVerificationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := startSpan(ctx, "deno_domain_verification.Create")
+	defer endSpan(span, &resp.Diagnostics)
+
 	// Retrieve values from plan
 	var plan domainVerificationResourceModel
 	diags := req.Plan.Get(ctx, &plan)
@@ -76,8 +133,7 @@ func (r *domainVerificationResource) Create(ctx context.Context, req resource.Cr
 		return
 	}
 
-	const DEFAULT_TIMEOUT = 10 * time.Minute
-	timeout, diags := plan.Timeouts.Create(ctx, DEFAULT_TIMEOUT)
+	timeout, diags := plan.Timeouts.Create(ctx, defaultDomainVerificationTimeout)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -92,41 +148,23 @@ func (r *domainVerificationResource) Create(ctx context.Context, req resource.Cr
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, timeout)
-	defer cancel()
+	// Check the DNS records before asking for the verification
+	diags = r.precheckDNS(ctx, plan, domainID, fmt.Sprintf("Unable to Create Domain Verification %s", plan.DomainID))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	ticker := time.NewTicker(20 * time.Second)
-	defer ticker.Stop()
-
-Polling:
-	for {
-		select {
-		// timeout
-		case <-ctx.Done():
-			resp.Diagnostics.AddError(
-				fmt.Sprintf("Unable to Create Domain Verification %s", plan.DomainID),
-				fmt.Sprintf("Timed out after %s", timeout),
-			)
-			return
-		// polling
-		case <-ticker.C:
-			// Call the API
-			result, err := r.client.VerifyDomainWithResponse(ctx, domainID)
-
-			if err != nil {
-				resp.Diagnostics.AddError(
-					fmt.Sprintf("Unable to Create Domain Verification %s", plan.DomainID),
-					fmt.Sprintf("veirfy API returned error: %s", err.Error()),
-				)
-				return
-			}
-			if client.RespIsError(result) {
-				continue
-			}
-
-			// Verification completed
-			break Polling
-		}
+	poller, diags := newPollerFromAttributes(plan.PollInterval, plan.MaxPollInterval, plan.BackoffMultiplier)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = r.waitForVerification(ctx, poller, domainID, timeout, fmt.Sprintf("Unable to Create Domain Verification %s", plan.DomainID))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
 	// Mark as verified
@@ -140,8 +178,83 @@ Polling:
 	}
 }
 
+// waitForVerification asks Deno Deploy to verify the domain until it succeeds
+// or the timeout expires.
+func (r *domainVerificationResource) waitForVerification(ctx context.Context, poller *poller, domainID uuid.UUID, timeout time.Duration, summary string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := poller.poll(ctx, func(ctx context.Context, attempt int) (bool, error) {
+		// Call the API
+		result, err := r.client.VerifyDomainWithResponse(ctx, domainID)
+		if err != nil {
+			return false, fmt.Errorf("veirfy API returned error: %w", err)
+		}
+		if client.RespIsError(result) {
+			detail := client.APIErrorDetail(result.HTTPResponse, result.Body)
+			tflog.Info(ctx, "Domain is not verified yet", map[string]any{
+				"domain_id": domainID.String(),
+				"attempt":   attempt,
+				"detail":    detail,
+			})
+			reportProgress(ctx, "Domain %s is not verified yet (attempt %d): %s", domainID, attempt, detail)
+			return false, nil
+		}
+
+		// Verification completed
+		return true, nil
+	})
+	if errors.Is(err, context.DeadlineExceeded) {
+		diags.AddError(summary, fmt.Sprintf("Timed out after %s", timeout))
+	} else if err != nil {
+		diags.AddError(summary, err.Error())
+	}
+	return diags
+}
+
+// precheckDNS resolves the DNS records that the domain requires and reports
+// an error describing the difference if any of them is missing or wrong.
+func (r *domainVerificationResource) precheckDNS(ctx context.Context, plan domainVerificationResourceModel, domainID uuid.UUID, summary string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if plan.SkipDNSPrecheck.ValueBool() {
+		return diags
+	}
+
+	domain, err := r.client.GetDomainWithResponse(ctx, domainID)
+	if err != nil {
+		diags.AddError(summary, fmt.Sprintf("Could not find domain with ID %s: %s", plan.DomainID, err.Error()))
+		return diags
+	}
+	if client.RespIsError(domain) {
+		diags.AddError(summary, client.APIErrorDetail(domain.HTTPResponse, domain.Body))
+		return diags
+	}
+
+	reportProgress(ctx, "Checking the DNS records of %s", domain.JSON200.Domain)
+	checker := newDNSChecker(plan.DNSResolver.ValueString())
+	checks, err := checker.checkRecords(ctx, domain.JSON200.Domain, domain.JSON200.DnsRecords)
+	if err != nil {
+		diags.AddError(summary, fmt.Sprintf("Could not check the DNS records of %s: %s", domain.JSON200.Domain, err.Error()))
+		return diags
+	}
+
+	if diff := dnsRecordChecksDiff(checks); diff != "" {
+		diags.AddError(
+			summary,
+			fmt.Sprintf("The DNS records of %s do not match the ones Deno Deploy expects. Add or fix the following records, or set skip_dns_precheck to true if they are not visible from here.\n\n%s", domain.JSON200.Domain, diff),
+		)
+	}
+	return diags
+}
+
 // Read refreshes the Terraform state with the latest data.
 func (r *domainVerificationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := startSpan(ctx, "deno_domain_verification.Read")
+	defer endSpan(span, &resp.Diagnostics)
+
 	// Get current state
 	var state domainVerificationResourceModel
 	diags := req.State.Get(ctx, &state)
@@ -184,6 +297,9 @@ func (r *domainVerificationResource) Read(ctx context.Context, req resource.Read
 
 // Update updates the resource and sets the updated Terraform state on success.
 func (r *domainVerificationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := startSpan(ctx, "deno_domain_verification.Update")
+	defer endSpan(span, &resp.Diagnostics)
+
 	// Follow the same procedure as Create
 
 	// Retrieve values from plan
@@ -194,8 +310,7 @@ func (r *domainVerificationResource) Update(ctx context.Context, req resource.Up
 		return
 	}
 
-	const DEFAULT_TIMEOUT = 10 * time.Minute
-	timeout, diags := plan.Timeouts.Create(ctx, DEFAULT_TIMEOUT)
+	timeout, diags := plan.Timeouts.Create(ctx, defaultDomainVerificationTimeout)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -210,41 +325,23 @@ func (r *domainVerificationResource) Update(ctx context.Context, req resource.Up
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, timeout)
-	defer cancel()
+	// Check the DNS records before asking for the verification
+	diags = r.precheckDNS(ctx, plan, domainID, fmt.Sprintf("Unable to Update Domain Verification %s", plan.DomainID))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	ticker := time.NewTicker(20 * time.Second)
-	defer ticker.Stop()
-
-Polling:
-	for {
-		select {
-		// timeout
-		case <-ctx.Done():
-			resp.Diagnostics.AddError(
-				fmt.Sprintf("Unable to Update Domain Verification %s", plan.DomainID),
-				fmt.Sprintf("Timed out after %s", timeout),
-			)
-			return
-		// polling
-		case <-ticker.C:
-			// Call the API
-			result, err := r.client.VerifyDomainWithResponse(ctx, domainID)
-
-			if err != nil {
-				resp.Diagnostics.AddError(
-					fmt.Sprintf("Unable to Update Domain Verification %s", plan.DomainID),
-					fmt.Sprintf("veirfy API returned error: %s", err.Error()),
-				)
-				return
-			}
-			if client.RespIsError(result) {
-				continue
-			}
-
-			// Verification completed
-			break Polling
-		}
+	poller, diags := newPollerFromAttributes(plan.PollInterval, plan.MaxPollInterval, plan.BackoffMultiplier)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = r.waitForVerification(ctx, poller, domainID, timeout, fmt.Sprintf("Unable to Update Domain Verification %s", plan.DomainID))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
 	// Mark as verified
@@ -259,8 +356,30 @@ Polling:
 }
 
 // Delete deletes the resource and removes the Terraform state on success.
+// The ownership verification can't be revoked, so this only warns that the
+// domain stays verified.
 func (r *domainVerificationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	// noop
+	ctx, span := startSpan(ctx, "deno_domain_verification.Delete")
+	defer endSpan(span, &resp.Diagnostics)
+
+	// Retrieve values from state
+	var state domainVerificationResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.AddWarning(
+		fmt.Sprintf("Domain %s Stays Verified", state.DomainID.ValueString()),
+		"Deno Deploy has no API to revoke the ownership verification of a domain, so the domain stays verified. Delete the domain, e.g. by destroying deno_domain resource, to undo the verification.",
+	)
+}
+
+// UpgradeState returns the upgraders of the state written by the prior
+// versions of the schema, of which there are none yet.
+func (r *domainVerificationResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{}
 }
 
 // Configure adds the provider configured client to the resource.
@@ -280,6 +399,11 @@ func (r *domainVerificationResource) Configure(_ context.Context, req resource.C
 		return
 	}
 
+	resp.Diagnostics.Append(providerData.connect()...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	r.client = providerData.client
 	r.organizationID = providerData.organizationID
 }
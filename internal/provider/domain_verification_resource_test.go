@@ -0,0 +1,39 @@
+package provider_test
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDomainVerification_MissingDNSRecords(t *testing.T) {
+	domain := randomDomainName()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccDomainDestroy(t),
+		Steps: []resource.TestStep{
+			{
+				// The records were never created, so the verification fails
+				// right away instead of waiting for the timeout
+				Config: fmt.Sprintf(`
+					resource "deno_domain" "test" {
+						domain = "%s"
+					}
+
+					resource "deno_domain_verification" "test" {
+						domain_id = deno_domain.test.id
+
+						timeouts = {
+							create = "30m"
+						}
+					}
+				`, domain),
+				ExpectError: regexp.MustCompile(`(?s)do not match the ones Deno Deploy expects.*missing`),
+			},
+		},
+	})
+}
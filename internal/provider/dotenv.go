@@ -0,0 +1,318 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+)
+
+// dotenvParseError is an error found while parsing a dotenv file.
+type dotenvParseError struct {
+	Line    int
+	Message string
+}
+
+func (e *dotenvParseError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// dotenvEntry is a single variable defined in a dotenv file.
+type dotenvEntry struct {
+	Key   string
+	Value string
+	Line  int
+}
+
+// dotenvParser parses the dotenv syntax:
+//
+//	# comment
+//	export KEY=value # inline comment
+//	SINGLE='literal, may span
+//	multiple lines'
+//	DOUBLE="escapes \n, \t, \", \\ and \$ and expansion of ${KEY} or $KEY"
+//	DEFAULT=${UNDEFINED:-fallback}
+//
+// Variables are expanded with the ones defined earlier in the same file, then
+// with the given lookup function. Undefined variables expand to an empty
+// string.
+type dotenvParser struct {
+	src    []rune
+	pos    int
+	line   int
+	lookup func(key string) (string, bool)
+	vars   map[string]string
+}
+
+// parseDotenv parses the content of a dotenv file.
+func parseDotenv(content string, lookup func(key string) (string, bool)) ([]dotenvEntry, error) {
+	p := &dotenvParser{
+		src:    []rune(strings.ReplaceAll(content, "\r\n", "\n")),
+		line:   1,
+		lookup: lookup,
+		vars:   map[string]string{},
+	}
+	return p.parse()
+}
+
+func (p *dotenvParser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *dotenvParser) peek() rune {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *dotenvParser) next() rune {
+	r := p.src[p.pos]
+	p.pos++
+	if r == '\n' {
+		p.line++
+	}
+	return r
+}
+
+func (p *dotenvParser) errorf(line int, format string, args ...any) error {
+	return &dotenvParseError{Line: line, Message: fmt.Sprintf(format, args...)}
+}
+
+func (p *dotenvParser) skipBlanks() {
+	for !p.eof() && (p.peek() == ' ' || p.peek() == '\t') {
+		p.next()
+	}
+}
+
+func (p *dotenvParser) skipToEndOfLine() {
+	for !p.eof() && p.peek() != '\n' {
+		p.next()
+	}
+}
+
+// isDotenvKeyRune reports whether the rune may appear in a variable name. The
+// names are the ones matched by envVarKeyRegexp, so that the variables parsed
+// are accepted by `env_vars`.
+func isDotenvKeyRune(r rune, first bool) bool {
+	switch {
+	case r == '_', r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z':
+		return true
+	case r >= '0' && r <= '9':
+		return !first
+	}
+	return false
+}
+
+func (p *dotenvParser) readKey() string {
+	start := p.pos
+	for !p.eof() && isDotenvKeyRune(p.peek(), p.pos == start) {
+		p.next()
+	}
+	return string(p.src[start:p.pos])
+}
+
+func (p *dotenvParser) parse() ([]dotenvEntry, error) {
+	entries := []dotenvEntry{}
+
+	for {
+		// Skip blank lines and comments
+		for !p.eof() {
+			switch p.peek() {
+			case ' ', '\t', '\n':
+				p.next()
+				continue
+			case '#':
+				p.skipToEndOfLine()
+				continue
+			}
+			break
+		}
+		if p.eof() {
+			return entries, nil
+		}
+
+		line := p.line
+		key := p.readKey()
+		if key == "export" && (p.peek() == ' ' || p.peek() == '\t') {
+			p.skipBlanks()
+			key = p.readKey()
+		}
+		if key == "" {
+			return nil, p.errorf(line, "expected a variable name, found %q", p.peek())
+		}
+		if !p.eof() && !strings.ContainsRune(" \t\n=", p.peek()) {
+			return nil, p.errorf(line, "invalid character %q in variable name %s, which may only contain letters, digits and underscores", p.peek(), key)
+		}
+
+		p.skipBlanks()
+		if p.eof() || p.peek() != '=' {
+			return nil, p.errorf(line, "expected '=' after variable name %s", key)
+		}
+		p.next()
+		p.skipBlanks()
+
+		var (
+			value string
+			err   error
+		)
+		switch p.peek() {
+		case '\'':
+			value, err = p.readSingleQuoted()
+		case '"':
+			value, err = p.readDoubleQuoted()
+		default:
+			value, err = p.readUnquoted()
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// Only a comment may follow the value
+		p.skipBlanks()
+		if p.peek() == '#' {
+			p.skipToEndOfLine()
+		}
+		if !p.eof() && p.peek() != '\n' {
+			return nil, p.errorf(p.line, "unexpected character %q after the value of %s", p.peek(), key)
+		}
+
+		p.vars[key] = value
+		entries = append(entries, dotenvEntry{Key: key, Value: value, Line: line})
+	}
+}
+
+func (p *dotenvParser) readSingleQuoted() (string, error) {
+	line := p.line
+	p.next() // opening quote
+
+	var sb strings.Builder
+	for !p.eof() {
+		r := p.next()
+		if r == '\'' {
+			return sb.String(), nil
+		}
+		sb.WriteRune(r)
+	}
+	return "", p.errorf(line, "unterminated single-quoted value")
+}
+
+func (p *dotenvParser) readDoubleQuoted() (string, error) {
+	line := p.line
+	p.next() // opening quote
+
+	var sb strings.Builder
+	for !p.eof() {
+		r := p.next()
+		switch r {
+		case '"':
+			return sb.String(), nil
+		case '\\':
+			if p.eof() {
+				return "", p.errorf(line, "unterminated double-quoted value")
+			}
+			escaped := p.next()
+			switch escaped {
+			case 'n':
+				sb.WriteRune('\n')
+			case 'r':
+				sb.WriteRune('\r')
+			case 't':
+				sb.WriteRune('\t')
+			case '"', '\\', '$', '\'':
+				sb.WriteRune(escaped)
+			case '\n':
+				// Line continuation
+			default:
+				return "", p.errorf(p.line, "invalid escape sequence \\%c", escaped)
+			}
+		case '$':
+			expanded, err := p.expand()
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(expanded)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return "", p.errorf(line, "unterminated double-quoted value")
+}
+
+func (p *dotenvParser) readUnquoted() (string, error) {
+	var sb strings.Builder
+	for !p.eof() && p.peek() != '\n' {
+		r := p.peek()
+		// A `#` preceded by a blank starts an inline comment
+		if r == '#' && (sb.Len() == 0 || strings.HasSuffix(sb.String(), " ") || strings.HasSuffix(sb.String(), "\t")) {
+			break
+		}
+		p.next()
+		if r == '$' {
+			expanded, err := p.expand()
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(expanded)
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return strings.TrimRight(sb.String(), " \t"), nil
+}
+
+// expand expands a variable reference right after `$`. Both `$KEY` and
+// `${KEY}` are supported, as well as `${KEY:-default}`. A `$` that doesn't
+// start a reference is kept as is.
+func (p *dotenvParser) expand() (string, error) {
+	line := p.line
+
+	if p.peek() != '{' {
+		key := p.readKey()
+		if key == "" {
+			return "$", nil
+		}
+		return p.resolve(key), nil
+	}
+
+	p.next() // `{`
+	key := p.readKey()
+	if key == "" {
+		return "", p.errorf(line, "invalid variable reference: expected a variable name after ${")
+	}
+
+	var fallback *string
+	if p.peek() == ':' {
+		p.next()
+		if p.eof() || p.next() != '-' {
+			return "", p.errorf(line, "invalid variable reference ${%s: only ${%s:-default} is supported", key, key)
+		}
+		var sb strings.Builder
+		for !p.eof() && p.peek() != '}' && p.peek() != '\n' {
+			sb.WriteRune(p.next())
+		}
+		s := sb.String()
+		fallback = &s
+	}
+
+	if p.eof() || p.peek() != '}' {
+		return "", p.errorf(line, "unterminated variable reference ${%s", key)
+	}
+	p.next() // `}`
+
+	value := p.resolve(key)
+	if value == "" && fallback != nil {
+		return *fallback, nil
+	}
+	return value, nil
+}
+
+func (p *dotenvParser) resolve(key string) string {
+	if v, ok := p.vars[key]; ok {
+		return v
+	}
+	if p.lookup != nil {
+		if v, ok := p.lookup(key); ok {
+			return v
+		}
+	}
+	return ""
+}
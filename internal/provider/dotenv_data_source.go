@@ -0,0 +1,131 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource = &dotenvDataSource{}
+)
+
+func NewDotenvDataSource() datasource.DataSource {
+	return &dotenvDataSource{}
+}
+
+type dotenvDataSource struct{}
+
+func (d *dotenvDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_dotenv"
+}
+
+// Schema defines the schema for the data source.
+func (d *dotenvDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: `
+A data source that parses .env files into a map of environment variables.
+
+The output can be passed to the env_vars or secret_env_vars attribute of deno_deployment resource as is.
+Supported syntax includes comments, single and double quotes, escape sequences in double quotes, the "export" prefix, multi-line quoted values, and expansion of ${VAR}, $VAR and ${VAR:-default}.
+		`,
+		Attributes: map[string]schema.Attribute{
+			"files": schema.ListAttribute{
+				Required:    true,
+				ElementType: types.StringType,
+				Description: "The paths to the .env files. When a variable is defined in more than one file, the value from the later file takes precedence. e.g. `[\".env\", \".env.production\"]`",
+			},
+			"expand_from_environment": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether to expand references to variables that are not defined in the files with the environment variables of the Terraform process. Defaults to `false`, in which case such references expand to an empty string.",
+			},
+			"output": schema.MapAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				ElementType: types.StringType,
+				Description: "The parsed environment variables.",
+			},
+		},
+	}
+}
+
+// dotenvDataSourceModel maps the data source schema data.
+type dotenvDataSourceModel struct {
+	Files                 []types.String `tfsdk:"files"`
+	ExpandFromEnvironment types.Bool     `tfsdk:"expand_from_environment"`
+	Output                types.Map      `tfsdk:"output"`
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *dotenvDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	// Retrieve values from config
+	var config dotenvDataSourceModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	vars := map[string]string{}
+	lookup := func(key string) (string, bool) {
+		if v, ok := vars[key]; ok {
+			return v, true
+		}
+		if config.ExpandFromEnvironment.ValueBool() {
+			return os.LookupEnv(key)
+		}
+		return "", false
+	}
+
+	for i, file := range config.Files {
+		filePath := file.ValueString()
+		b, err := os.ReadFile(filePath)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("files").AtListIndex(i),
+				fmt.Sprintf("Unable to Read Dotenv File %s", filePath),
+				err.Error(),
+			)
+			return
+		}
+
+		entries, err := parseDotenv(string(b), lookup)
+		if err != nil {
+			detail := err.Error()
+			var parseErr *dotenvParseError
+			if errors.As(err, &parseErr) {
+				detail = fmt.Sprintf("%s:%d: %s", filePath, parseErr.Line, parseErr.Message)
+			}
+			resp.Diagnostics.AddAttributeError(
+				path.Root("files").AtListIndex(i),
+				fmt.Sprintf("Unable to Parse Dotenv File %s", filePath),
+				detail,
+			)
+			return
+		}
+
+		for _, entry := range entries {
+			vars[entry.Key] = entry.Value
+		}
+	}
+
+	output, diags := types.MapValueFrom(ctx, types.StringType, vars)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	config.Output = output
+
+	diags = resp.State.Set(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
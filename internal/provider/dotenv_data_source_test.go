@@ -0,0 +1,48 @@
+package provider_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDotenv_MultipleFiles(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					data "deno_dotenv" "test" {
+						files = ["./testdata/dotenv/.env", "./testdata/dotenv/.env.production"]
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.deno_dotenv.test", "output.%", "5"),
+					resource.TestCheckResourceAttr("data.deno_dotenv.test", "output.APP_NAME", "my-app"),
+					resource.TestCheckResourceAttr("data.deno_dotenv.test", "output.BASE_URL", "https://example.com"),
+					resource.TestCheckResourceAttr("data.deno_dotenv.test", "output.LOG_LEVEL", "warn"),
+					resource.TestCheckResourceAttr("data.deno_dotenv.test", "output.GREETING", "Hello\nworld"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDotenv_MissingFile(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					data "deno_dotenv" "test" {
+						files = ["./testdata/dotenv/.env.missing"]
+					}
+				`,
+				ExpectError: regexp.MustCompile("Unable to Read Dotenv File"),
+			},
+		},
+	})
+}
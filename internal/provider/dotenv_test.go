@@ -0,0 +1,179 @@
+package provider
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseDotenv(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		lookup   map[string]string
+		expected map[string]string
+	}{
+		{
+			name:     "blank",
+			input:    "",
+			expected: map[string]string{},
+		},
+		{
+			name: "comments and blank lines",
+			input: `
+# comment
+FOO=bar
+
+  # indented comment
+BAZ=qux
+`,
+			expected: map[string]string{"FOO": "bar", "BAZ": "qux"},
+		},
+		{
+			name:     "export prefix and spaces around equals",
+			input:    "export FOO = bar\nexport\tBAZ=qux",
+			expected: map[string]string{"FOO": "bar", "BAZ": "qux"},
+		},
+		{
+			name:     "variable named export",
+			input:    "export=1",
+			expected: map[string]string{"export": "1"},
+		},
+		{
+			name:     "inline comment on unquoted value",
+			input:    "FOO=bar # comment\nURL=http://example.com/#anchor",
+			expected: map[string]string{"FOO": "bar", "URL": "http://example.com/#anchor"},
+		},
+		{
+			name:     "empty values",
+			input:    "A=\nB=''\nC=\"\"",
+			expected: map[string]string{"A": "", "B": "", "C": ""},
+		},
+		{
+			name:     "single quotes are literal",
+			input:    `FOO='a \n ${BAR} # not a comment'`,
+			expected: map[string]string{"FOO": `a \n ${BAR} # not a comment`},
+		},
+		{
+			name:     "double quote escapes",
+			input:    `FOO="line1\nline2\t\"quoted\" \\ \$HOME"`,
+			expected: map[string]string{"FOO": "line1\nline2\t\"quoted\" \\ $HOME"},
+		},
+		{
+			name:     "multi-line values",
+			input:    "KEY=\"-----BEGIN KEY-----\nabc\n-----END KEY-----\"\nSINGLE='a\nb'\nNEXT=1",
+			expected: map[string]string{"KEY": "-----BEGIN KEY-----\nabc\n-----END KEY-----", "SINGLE": "a\nb", "NEXT": "1"},
+		},
+		{
+			name:     "comment after quoted value",
+			input:    `FOO="bar" # comment`,
+			expected: map[string]string{"FOO": "bar"},
+		},
+		{
+			name:     "expansion",
+			input:    "HOST=example.com\nURL=https://${HOST}/path\nURL2=\"https://$HOST/\"\nPRICE=$5",
+			expected: map[string]string{"HOST": "example.com", "URL": "https://example.com/path", "URL2": "https://example.com/", "PRICE": "$5"},
+		},
+		{
+			name:     "expansion with lookup and default",
+			input:    "A=${FROM_LOOKUP}\nB=${UNDEFINED:-fallback}\nC=${UNDEFINED}",
+			lookup:   map[string]string{"FROM_LOOKUP": "looked up"},
+			expected: map[string]string{"A": "looked up", "B": "fallback", "C": ""},
+		},
+		{
+			name:     "CRLF line endings",
+			input:    "FOO=bar\r\nBAZ=qux\r\n",
+			expected: map[string]string{"FOO": "bar", "BAZ": "qux"},
+		},
+		{
+			name:     "later definition wins",
+			input:    "FOO=1\nFOO=2",
+			expected: map[string]string{"FOO": "2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lookup := func(key string) (string, bool) {
+				v, ok := tt.lookup[key]
+				return v, ok
+			}
+			entries, err := parseDotenv(tt.input, lookup)
+			if err != nil {
+				t.Fatalf("parseDotenv() returned unexpected error: %s", err)
+			}
+			got := map[string]string{}
+			for _, e := range entries {
+				got[e.Key] = e.Value
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("parseDotenv() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseDotenv_Errors(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		expectedLine int
+	}{
+		{
+			name:         "missing equals",
+			input:        "FOO=bar\nBAZ",
+			expectedLine: 2,
+		},
+		{
+			name:         "invalid variable name",
+			input:        "\n\n1FOO=bar",
+			expectedLine: 3,
+		},
+		{
+			name:         "dot in variable name",
+			input:        "A=1\nFOO.BAR=baz",
+			expectedLine: 2,
+		},
+		{
+			name:         "unterminated double quote",
+			input:        "A=1\nFOO=\"bar\nbaz",
+			expectedLine: 2,
+		},
+		{
+			name:         "unterminated single quote",
+			input:        "FOO='bar",
+			expectedLine: 1,
+		},
+		{
+			name:         "garbage after quoted value",
+			input:        "FOO=\"bar\"baz",
+			expectedLine: 1,
+		},
+		{
+			name:         "unterminated variable reference",
+			input:        "A=1\nB=2\nFOO=${BAR",
+			expectedLine: 3,
+		},
+		{
+			name:         "invalid escape",
+			input:        `FOO="\q"`,
+			expectedLine: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseDotenv(tt.input, nil)
+			if err == nil {
+				t.Fatal("parseDotenv() should fail")
+			}
+			var parseErr *dotenvParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("parseDotenv() returned unexpected error type %T", err)
+			}
+			if parseErr.Line != tt.expectedLine {
+				t.Errorf("error line = %d, want %d (%s)", parseErr.Line, tt.expectedLine, parseErr.Message)
+			}
+		})
+	}
+}
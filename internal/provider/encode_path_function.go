@@ -0,0 +1,45 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ function.Function = &encodePathFunction{}
+
+func NewEncodePathFunction() function.Function {
+	return &encodePathFunction{}
+}
+
+type encodePathFunction struct{}
+
+func (f *encodePathFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "encode_path"
+}
+
+func (f *encodePathFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "URL-encode a path",
+		Description: "Applies URL encoding to each segment of the given path, preserving the directory separator `/`. This is the encoding the provider uses for asset paths.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "path",
+				Description: "The path to encode.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *encodePathFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var path string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &path))
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, encodePath(path)))
+}
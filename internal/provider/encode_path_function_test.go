@@ -0,0 +1,29 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestEncodePathFunction(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{path: "main.ts", expected: "main.ts"},
+		{path: "src/main.ts", expected: "src/main.ts"},
+		{path: "static/hello world.txt", expected: "static/hello+world.txt"},
+		{path: "a&b/c?d.ts", expected: "a%26b/c%3Fd.ts"},
+	}
+
+	for _, tt := range tests {
+		got, err := runStringFunction(t, NewEncodePathFunction(), types.StringValue(tt.path))
+		if err != nil {
+			t.Fatalf("encode_path(%q) returned unexpected error: %s", tt.path, err)
+		}
+		if got.ValueString() != tt.expected {
+			t.Errorf("encode_path(%q) = %s, want %s", tt.path, got.ValueString(), tt.expected)
+		}
+	}
+}
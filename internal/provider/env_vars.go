@@ -0,0 +1,198 @@
+package provider
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Limits that Deno Deploy applies to the environment variables of a
+// deployment, as documented in
+// https://docs.deno.com/deploy/classic/environment-variables/#limitations.
+const (
+	maxEnvVarKeyLength = 128
+	maxEnvVarValueSize = 16 * 1024
+)
+
+// reservedEnvVarPrefixes are the prefixes of the names that Deno Deploy
+// doesn't allow to set, which are used by the platform and the runtime. The
+// names are case-sensitive.
+var reservedEnvVarPrefixes = []string{"DENO_", "LD_", "OTEL_"}
+
+// allowedDenoEnvVars are the names starting with DENO_ that Deno Deploy
+// allows to set nonetheless, as they configure the runtime.
+var allowedDenoEnvVars = []string{"DENO_AUTH_TOKENS", "DENO_COMPAT", "DENO_CONDITIONS", "DENO_DEPLOY_ENDPOINT", "DENO_DEPLOY_TOKEN"}
+
+var envVarKeyRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// reservedEnvVarPrefix returns the reserved prefix the name starts with, or
+// an empty string if it can be set.
+func reservedEnvVarPrefix(key string) string {
+	if slices.Contains(allowedDenoEnvVars, key) {
+		return ""
+	}
+	for _, prefix := range reservedEnvVarPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return prefix
+		}
+	}
+	return ""
+}
+
+// validateEnvVar returns the reason why the given environment variable is
+// rejected by Deno Deploy, or an empty string if it is valid. A nil value
+// means the value is not known yet.
+func validateEnvVar(key string, value *string) string {
+	switch {
+	case key == "":
+		return "The environment variable name must not be empty."
+	case len(key) > maxEnvVarKeyLength:
+		return fmt.Sprintf("The environment variable name must be at most %d bytes long, got %d.", maxEnvVarKeyLength, len(key))
+	case !envVarKeyRegexp.MatchString(key):
+		return fmt.Sprintf("The environment variable name %q is invalid. It must start with a letter or an underscore, and contain only letters, digits and underscores.", key)
+	case reservedEnvVarPrefix(key) != "":
+		return fmt.Sprintf("The environment variable name %q is reserved. Names starting with %s can't be set on Deno Deploy.", key, reservedEnvVarPrefix(key))
+	case value != nil && len(*value) > maxEnvVarValueSize:
+		return fmt.Sprintf("The value of %s is %d bytes, which exceeds the limit of %d bytes.", key, len(*value), maxEnvVarValueSize)
+	}
+	return ""
+}
+
+var _ validator.Map = envVarsValidator{}
+
+// envVarsValidator validates a map of environment variables against the rules
+// of Deno Deploy, so that invalid variables are reported by `terraform
+// validate` instead of by the API after a full upload.
+type envVarsValidator struct{}
+
+func (v envVarsValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("environment variable names must match %s, must not start with %s except for %s, and be at most %d bytes long; values must be at most %d bytes", envVarKeyRegexp, strings.Join(reservedEnvVarPrefixes, ", "), strings.Join(allowedDenoEnvVars, ", "), maxEnvVarKeyLength, maxEnvVarValueSize)
+}
+
+func (v envVarsValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v envVarsValidator) ValidateMap(_ context.Context, req validator.MapRequest, resp *validator.MapResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	elements := req.ConfigValue.Elements()
+	keys := make([]string, 0, len(elements))
+	for k := range elements {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		var value *string
+		if s, ok := elements[k].(types.String); ok && !s.IsUnknown() && !s.IsNull() {
+			v := s.ValueString()
+			value = &v
+		}
+		if reason := validateEnvVar(k, value); reason != "" {
+			resp.Diagnostics.AddAttributeError(
+				req.Path.AtMapKey(k),
+				"Invalid Environment Variable",
+				reason,
+			)
+		}
+	}
+}
+
+// envVarSource is a map of environment variables along with the attribute it
+// comes from.
+type envVarSource struct {
+	attribute string
+	vars      map[string]string
+}
+
+// mergeEnvVars merges the given environment variable sources into a single
+// map. A key defined in more than one source is reported as a conflict.
+func mergeEnvVars(sources ...envVarSource) (map[string]string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	merged := map[string]string{}
+	definedIn := map[string]string{}
+	for _, source := range sources {
+		keys := make([]string, 0, len(source.vars))
+		for k := range source.vars {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			if prev, ok := definedIn[k]; ok {
+				diags.AddAttributeError(
+					path.Root(source.attribute).AtMapKey(k),
+					"Conflicting Environment Variable",
+					fmt.Sprintf("The environment variable %s is defined in both `%s` and `%s`. Each variable can only be defined once.", k, prev, source.attribute),
+				)
+				continue
+			}
+			definedIn[k] = source.attribute
+			merged[k] = source.vars[k]
+		}
+	}
+
+	return merged, diags
+}
+
+// envVarsSaltSize is the size of the random salt that keys hashEnvVars.
+const envVarsSaltSize = 16
+
+// newEnvVarsSalt returns a random salt for hashEnvVars.
+func newEnvVarsSalt() ([]byte, error) {
+	salt := make([]byte, envVarsSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
+
+// hashEnvVars returns the HMAC-SHA256 digest of the given environment
+// variables keyed with the salt, as the hex-encoded salt and digest joined by
+// a colon. Keying it with a random salt per resource prevents matching the
+// digest against precomputed ones of common values. The digest doesn't depend
+// on the iteration order of the map.
+func hashEnvVars(vars map[string]string, salt []byte) string {
+	keys := make([]string, 0, len(vars))
+	for k := range vars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := hmac.New(sha256.New, salt)
+	for _, k := range keys {
+		// Length-prefix each part so that different maps can't collide by
+		// shifting bytes between keys and values.
+		fmt.Fprintf(h, "%d:%s%d:%s", len(k), k, len(vars[k]), vars[k])
+	}
+	return hex.EncodeToString(salt) + ":" + hex.EncodeToString(h.Sum(nil))
+}
+
+// envVarsHashSalt returns the salt of a digest returned by hashEnvVars.
+func envVarsHashSalt(digest string) ([]byte, bool) {
+	encodedSalt, _, ok := strings.Cut(digest, ":")
+	if !ok {
+		return nil, false
+	}
+	salt, err := hex.DecodeString(encodedSalt)
+	if err != nil || len(salt) == 0 {
+		return nil, false
+	}
+	return salt, true
+}
@@ -0,0 +1,178 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestMergeEnvVars(t *testing.T) {
+	merged, diags := mergeEnvVars(
+		envVarSource{attribute: "env_vars", vars: map[string]string{"FOO": "1", "BAR": "2"}},
+		envVarSource{attribute: "secret_env_vars", vars: map[string]string{"SECRET": "s"}},
+		envVarSource{attribute: "secret_env_vars_wo", vars: nil},
+	)
+	if diags.HasError() {
+		t.Fatalf("mergeEnvVars() returned unexpected errors: %v", diags)
+	}
+	expected := map[string]string{"FOO": "1", "BAR": "2", "SECRET": "s"}
+	if !reflect.DeepEqual(merged, expected) {
+		t.Errorf("mergeEnvVars() = %v, want %v", merged, expected)
+	}
+}
+
+func TestMergeEnvVars_Conflict(t *testing.T) {
+	_, diags := mergeEnvVars(
+		envVarSource{attribute: "env_vars", vars: map[string]string{"FOO": "1"}},
+		envVarSource{attribute: "secret_env_vars", vars: map[string]string{"FOO": "2"}},
+	)
+	if diags.ErrorsCount() != 1 {
+		t.Fatalf("mergeEnvVars() should report exactly one conflict, got %v", diags)
+	}
+	detail := diags.Errors()[0].Detail()
+	if !strings.Contains(detail, "FOO") || !strings.Contains(detail, "`env_vars`") || !strings.Contains(detail, "`secret_env_vars`") {
+		t.Errorf("unexpected conflict detail: %s", detail)
+	}
+}
+
+func TestHashEnvVars(t *testing.T) {
+	salt := []byte("0123456789abcdef")
+
+	a := hashEnvVars(map[string]string{"FOO": "1", "BAR": "2"}, salt)
+	b := hashEnvVars(map[string]string{"BAR": "2", "FOO": "1"}, salt)
+	if a != b {
+		t.Errorf("hashEnvVars() should not depend on map order: %s != %s", a, b)
+	}
+
+	c := hashEnvVars(map[string]string{"FOO": "12"}, salt)
+	d := hashEnvVars(map[string]string{"FOO1": "2"}, salt)
+	if c == d {
+		t.Errorf("hashEnvVars() should distinguish key/value boundaries")
+	}
+
+	e := hashEnvVars(map[string]string{"FOO": "1", "BAR": "2"}, []byte("fedcba9876543210"))
+	if a == e {
+		t.Errorf("hashEnvVars() should depend on the salt")
+	}
+}
+
+func TestEnvVarsHashSalt(t *testing.T) {
+	salt, err := newEnvVarsSalt()
+	if err != nil {
+		t.Fatalf("newEnvVarsSalt() returned unexpected error: %s", err)
+	}
+
+	got, ok := envVarsHashSalt(hashEnvVars(map[string]string{"FOO": "1"}, salt))
+	if !ok || !bytes.Equal(got, salt) {
+		t.Errorf("envVarsHashSalt() = %x, %v, want %x", got, ok, salt)
+	}
+	for _, invalid := range []string{"", "not-a-digest", "zz:00", ":00"} {
+		if _, ok := envVarsHashSalt(invalid); ok {
+			t.Errorf("envVarsHashSalt(%q) should fail", invalid)
+		}
+	}
+}
+
+func TestEnvVarsValidator(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         types.Map
+		expectedPaths []path.Path
+	}{
+		{
+			name:  "null",
+			value: types.MapNull(types.StringType),
+		},
+		{
+			name:  "unknown",
+			value: types.MapUnknown(types.StringType),
+		},
+		{
+			name: "valid",
+			value: types.MapValueMust(types.StringType, map[string]attr.Value{
+				"FOO":          types.StringValue("bar"),
+				"_PRIVATE":     types.StringValue(""),
+				"DATABASE_URL": types.StringUnknown(),
+			}),
+		},
+		{
+			name: "invalid names",
+			value: types.MapValueMust(types.StringType, map[string]attr.Value{
+				"1FOO":    types.StringValue("bar"),
+				"FOO-BAR": types.StringValue("bar"),
+				"":        types.StringValue("bar"),
+			}),
+			expectedPaths: []path.Path{
+				path.Root("env_vars").AtMapKey(""),
+				path.Root("env_vars").AtMapKey("1FOO"),
+				path.Root("env_vars").AtMapKey("FOO-BAR"),
+			},
+		},
+		{
+			name: "reserved prefix",
+			value: types.MapValueMust(types.StringType, map[string]attr.Value{
+				"DENO_REGION":      types.StringValue("us-east4"),
+				"LD_PRELOAD":       types.StringValue("lib.so"),
+				"OTEL_SDK_DISABLE": types.StringValue("true"),
+				"DENO_AUTH_TOKENS": types.StringValue("token@deno.land"),
+				"deno_foo":         types.StringValue("bar"),
+			}),
+			expectedPaths: []path.Path{
+				path.Root("env_vars").AtMapKey("DENO_REGION"),
+				path.Root("env_vars").AtMapKey("LD_PRELOAD"),
+				path.Root("env_vars").AtMapKey("OTEL_SDK_DISABLE"),
+			},
+		},
+		{
+			name: "oversized value",
+			value: types.MapValueMust(types.StringType, map[string]attr.Value{
+				"BIG": types.StringValue(strings.Repeat("x", maxEnvVarValueSize+1)),
+			}),
+			expectedPaths: []path.Path{
+				path.Root("env_vars").AtMapKey("BIG"),
+			},
+		},
+		{
+			name: "oversized name",
+			value: types.MapValueMust(types.StringType, map[string]attr.Value{
+				strings.Repeat("X", maxEnvVarKeyLength+1): types.StringValue("bar"),
+			}),
+			expectedPaths: []path.Path{
+				path.Root("env_vars").AtMapKey(strings.Repeat("X", maxEnvVarKeyLength+1)),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validator.MapRequest{
+				Path:        path.Root("env_vars"),
+				ConfigValue: tt.value,
+			}
+			resp := &validator.MapResponse{}
+			envVarsValidator{}.ValidateMap(context.Background(), req, resp)
+
+			var got []path.Path
+			for _, d := range resp.Diagnostics.Errors() {
+				if withPath, ok := d.(interface{ Path() path.Path }); ok {
+					got = append(got, withPath.Path())
+				}
+			}
+			if len(got) != len(tt.expectedPaths) {
+				t.Fatalf("expected %d errors, got %d: %v", len(tt.expectedPaths), len(got), resp.Diagnostics)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.expectedPaths[i]) {
+					t.Errorf("error %d is reported at %s, want %s", i, got[i], tt.expectedPaths[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ function.Function = &gitSha1FileFunction{}
+
+func NewGitSha1FileFunction() function.Function {
+	return &gitSha1FileFunction{}
+}
+
+type gitSha1FileFunction struct{}
+
+func (f *gitSha1FileFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "git_sha1_file"
+}
+
+func (f *gitSha1FileFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Compute the git SHA1 of a file",
+		Description: "Returns the git blob SHA1 of the content of the given file. Unlike `git_sha1(file(path))`, it also works with files that are not valid UTF-8.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "path",
+				Description: "The path of the file in the local filesystem.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *gitSha1FileFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var path string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &path))
+	if resp.Error != nil {
+		return
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("Could not read file %s: %s", path, err.Error()))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, calculateGitSha1(b)))
+}
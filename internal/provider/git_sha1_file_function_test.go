@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestGitSha1FileFunction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hello.txt")
+	if err := os.WriteFile(path, []byte("hello\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := runStringFunction(t, NewGitSha1FileFunction(), types.StringValue(path))
+	if err != nil {
+		t.Fatalf("git_sha1_file() returned unexpected error: %s", err)
+	}
+	if expected := "ce013625030ba8dba906f756967f9e9ca394464a"; got.ValueString() != expected {
+		t.Errorf("git_sha1_file() = %s, want %s", got.ValueString(), expected)
+	}
+}
+
+func TestGitSha1FileFunction_NonUTF8(t *testing.T) {
+	content := []byte{0xff, 0xfe, 0x00}
+	path := filepath.Join(t.TempDir(), "binary")
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := runStringFunction(t, NewGitSha1FileFunction(), types.StringValue(path))
+	if err != nil {
+		t.Fatalf("git_sha1_file() returned unexpected error: %s", err)
+	}
+	if expected := calculateGitSha1(content); got.ValueString() != expected {
+		t.Errorf("git_sha1_file() = %s, want %s", got.ValueString(), expected)
+	}
+}
+
+func TestGitSha1FileFunction_MissingFile(t *testing.T) {
+	_, err := runStringFunction(t, NewGitSha1FileFunction(), types.StringValue(filepath.Join(t.TempDir(), "missing")))
+	if err == nil {
+		t.Fatal("git_sha1_file() should fail for a missing file")
+	}
+	if err.FunctionArgument == nil || *err.FunctionArgument != 0 {
+		t.Errorf("error should point at the path argument, got %v", err.FunctionArgument)
+	}
+}
@@ -0,0 +1,45 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ function.Function = &gitSha1Function{}
+
+func NewGitSha1Function() function.Function {
+	return &gitSha1Function{}
+}
+
+type gitSha1Function struct{}
+
+func (f *gitSha1Function) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "git_sha1"
+}
+
+func (f *gitSha1Function) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Compute the git SHA1 of a string",
+		Description: "Returns the git blob SHA1 of the given content, the same value as the `git_sha1` attribute of a deployment asset.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "content",
+				Description: "The content to hash. It is hashed as UTF-8 bytes.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *gitSha1Function) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var content string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &content))
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, calculateGitSha1([]byte(content))))
+}
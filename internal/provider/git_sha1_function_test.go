@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// runStringFunction runs a function returning a string with the given
+// arguments.
+func runStringFunction(t *testing.T, f function.Function, args ...attr.Value) (types.String, *function.FuncError) {
+	t.Helper()
+
+	req := function.RunRequest{Arguments: function.NewArgumentsData(args)}
+	resp := &function.RunResponse{Result: function.NewResultData(types.StringUnknown())}
+	f.Run(context.Background(), req, resp)
+	if resp.Error != nil {
+		return types.StringNull(), resp.Error
+	}
+
+	result, ok := resp.Result.Value().(types.String)
+	if !ok {
+		t.Fatalf("Run() returned %T, want types.String", resp.Result.Value())
+	}
+	return result, nil
+}
+
+func TestGitSha1Function(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected string
+	}{
+		// Same as `printf '' | git hash-object --stdin`
+		{content: "", expected: "e69de29bb2d1d6434b8b29ae775ad8c2e48c5391"},
+		// Same as `echo hello | git hash-object --stdin`
+		{content: "hello\n", expected: "ce013625030ba8dba906f756967f9e9ca394464a"},
+	}
+
+	for _, tt := range tests {
+		got, err := runStringFunction(t, NewGitSha1Function(), types.StringValue(tt.content))
+		if err != nil {
+			t.Fatalf("git_sha1(%q) returned unexpected error: %s", tt.content, err)
+		}
+		if got.ValueString() != tt.expected {
+			t.Errorf("git_sha1(%q) = %s, want %s", tt.content, got.ValueString(), tt.expected)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	defaultHealthCheckPath           = "/"
+	defaultHealthCheckExpectedStatus = http.StatusOK
+	defaultHealthCheckRetries        = 5
+	defaultHealthCheckInterval       = 5 * time.Second
+
+	// maxHealthCheckBodySize is the maximum number of bytes of the response
+	// body that are read and attached to diagnostics.
+	maxHealthCheckBodySize = 4096
+)
+
+// healthCheckModel maps the health check schema data.
+type healthCheckModel struct {
+	Path           types.String `tfsdk:"path"`
+	ExpectedStatus types.Int64  `tfsdk:"expected_status"`
+	BodyRegex      types.String `tfsdk:"body_regex"`
+	Headers        types.Map    `tfsdk:"headers"`
+	Retries        types.Int64  `tfsdk:"retries"`
+	Interval       types.String `tfsdk:"interval"`
+}
+
+// healthCheckConfig is the resolved configuration of a health check, with the
+// defaults applied.
+type healthCheckConfig struct {
+	Path           string
+	ExpectedStatus int
+	BodyRegex      *regexp.Regexp
+	Headers        map[string]string
+	Retries        int
+	Interval       time.Duration
+}
+
+// toConfig converts the model to a healthCheckConfig, applying the defaults
+// to the omitted attributes. The unknown attributes, which are only found in
+// the configuration at validation, are taken as omitted.
+func (m *healthCheckModel) toConfig(ctx context.Context) (healthCheckConfig, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	cfg := healthCheckConfig{
+		Path:           defaultHealthCheckPath,
+		ExpectedStatus: defaultHealthCheckExpectedStatus,
+		Headers:        map[string]string{},
+		Retries:        defaultHealthCheckRetries,
+		Interval:       defaultHealthCheckInterval,
+	}
+
+	if p := m.Path.ValueString(); p != "" {
+		if !strings.HasPrefix(p, "/") {
+			p = "/" + p
+		}
+		cfg.Path = p
+	}
+
+	if !m.ExpectedStatus.IsNull() && !m.ExpectedStatus.IsUnknown() {
+		if status := m.ExpectedStatus.ValueInt64(); status < 100 || status > 599 {
+			diags.AddAttributeError(
+				path.Root("health_check").AtName("expected_status"),
+				"Invalid Health Check Expected Status",
+				fmt.Sprintf("The expected status must be an HTTP status code between 100 and 599, got %d", status),
+			)
+		}
+		cfg.ExpectedStatus = int(m.ExpectedStatus.ValueInt64())
+	}
+
+	if !m.BodyRegex.IsNull() && !m.BodyRegex.IsUnknown() {
+		re, err := regexp.Compile(m.BodyRegex.ValueString())
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("health_check").AtName("body_regex"),
+				"Invalid Health Check Body Regex",
+				fmt.Sprintf("Could not compile %q: %s", m.BodyRegex.ValueString(), err.Error()),
+			)
+		}
+		cfg.BodyRegex = re
+	}
+
+	if !m.Headers.IsNull() && !m.Headers.IsUnknown() {
+		diags.Append(m.Headers.ElementsAs(ctx, &cfg.Headers, false)...)
+	}
+
+	if !m.Retries.IsNull() && !m.Retries.IsUnknown() {
+		if m.Retries.ValueInt64() < 0 {
+			diags.AddAttributeError(
+				path.Root("health_check").AtName("retries"),
+				"Invalid Health Check Retries",
+				fmt.Sprintf("The number of retries must not be negative, got %d", m.Retries.ValueInt64()),
+			)
+		}
+		cfg.Retries = int(m.Retries.ValueInt64())
+	}
+
+	if !m.Interval.IsNull() && !m.Interval.IsUnknown() {
+		interval, err := time.ParseDuration(m.Interval.ValueString())
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("health_check").AtName("interval"),
+				"Invalid Health Check Interval",
+				fmt.Sprintf("Could not parse %q as a duration: %s", m.Interval.ValueString(), err.Error()),
+			)
+		} else if interval <= 0 {
+			diags.AddAttributeError(
+				path.Root("health_check").AtName("interval"),
+				"Invalid Health Check Interval",
+				fmt.Sprintf("The interval must be positive, got %s", m.Interval.ValueString()),
+			)
+		}
+		cfg.Interval = interval
+	}
+
+	return cfg, diags
+}
+
+// healthCheckResponse is the outcome of a single probe.
+type healthCheckResponse struct {
+	URL        string
+	StatusCode int
+	Body       string
+	Err        error
+}
+
+func (r healthCheckResponse) String() string {
+	if r.Err != nil {
+		return fmt.Sprintf("GET %s\nError: %s", r.URL, r.Err.Error())
+	}
+	return fmt.Sprintf("GET %s\nStatus: %d\nBody:\n%s", r.URL, r.StatusCode, r.Body)
+}
+
+// healthChecker probes deployments over HTTP.
+type healthChecker struct {
+	httpClient *http.Client
+}
+
+// check probes every base URL with the given configuration, retrying each of
+// them up to cfg.Retries times. It returns an error containing the last
+// response of the first URL that didn't pass.
+func (c *healthChecker) check(ctx context.Context, baseURLs []string, cfg healthCheckConfig) error {
+	for _, baseURL := range baseURLs {
+		if err := c.checkURL(ctx, strings.TrimSuffix(baseURL, "/")+cfg.Path, cfg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *healthChecker) checkURL(ctx context.Context, url string, cfg healthCheckConfig) error {
+	var (
+		last    healthCheckResponse
+		problem string
+	)
+
+	p := &poller{
+		Interval:    cfg.Interval,
+		MaxAttempts: cfg.Retries + 1,
+	}
+	err := p.poll(ctx, func(ctx context.Context, attempt int) (bool, error) {
+		last = c.probe(ctx, url, cfg.Headers)
+		problem = evaluateHealthCheckResponse(last, cfg)
+		tflog.Debug(ctx, "Health check attempt", map[string]any{
+			"url":     url,
+			"attempt": attempt,
+			"status":  last.StatusCode,
+			"problem": problem,
+		})
+		return problem == "", nil
+	})
+
+	var exhausted *pollAttemptsExhaustedError
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &exhausted):
+		return fmt.Errorf("health check failed after %d attempt(s): %s\n\nLast response:\n%s", exhausted.Attempts, problem, last)
+	default:
+		return fmt.Errorf("health check for %s was cancelled: %w", url, err)
+	}
+}
+
+func (c *healthChecker) probe(ctx context.Context, url string, headers map[string]string) healthCheckResponse {
+	res := healthCheckResponse{URL: url}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	httpClient := c.httpClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxHealthCheckBodySize))
+	if err != nil {
+		res.Err = err
+		return res
+	}
+
+	res.StatusCode = resp.StatusCode
+	res.Body = string(body)
+	return res
+}
+
+// evaluateHealthCheckResponse returns a description of why the response
+// doesn't pass the health check, or an empty string if it does.
+func evaluateHealthCheckResponse(res healthCheckResponse, cfg healthCheckConfig) string {
+	if res.Err != nil {
+		return "request failed"
+	}
+	if res.StatusCode != cfg.ExpectedStatus {
+		return fmt.Sprintf("expected status %d, got %d", cfg.ExpectedStatus, res.StatusCode)
+	}
+	if cfg.BodyRegex != nil && !cfg.BodyRegex.MatchString(res.Body) {
+		return fmt.Sprintf("response body does not match %q", cfg.BodyRegex.String())
+	}
+	return ""
+}
@@ -0,0 +1,185 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestHealthChecker(t *testing.T) {
+	tests := []struct {
+		name        string
+		handler     http.HandlerFunc
+		cfg         healthCheckConfig
+		expectedErr string
+	}{
+		{
+			name: "success",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("Hello world"))
+			},
+			cfg: healthCheckConfig{
+				Path:           "/",
+				ExpectedStatus: http.StatusOK,
+				BodyRegex:      regexp.MustCompile("^Hello"),
+			},
+		},
+		{
+			name: "unexpected status",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte("boom"))
+			},
+			cfg: healthCheckConfig{
+				Path:           "/",
+				ExpectedStatus: http.StatusOK,
+				Retries:        2,
+			},
+			expectedErr: "health check failed after 3 attempt(s): expected status 200, got 500",
+		},
+		{
+			name: "body mismatch",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("Goodbye world"))
+			},
+			cfg: healthCheckConfig{
+				Path:           "/",
+				ExpectedStatus: http.StatusOK,
+				BodyRegex:      regexp.MustCompile("^Hello"),
+			},
+			expectedErr: `response body does not match "^Hello"`,
+		},
+		{
+			name: "path and headers",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/healthz" || r.Header.Get("X-Probe") != "1" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.WriteHeader(http.StatusNoContent)
+			},
+			cfg: healthCheckConfig{
+				Path:           "/healthz",
+				ExpectedStatus: http.StatusNoContent,
+				Headers:        map[string]string{"X-Probe": "1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			c := &healthChecker{httpClient: server.Client()}
+			err := c.check(context.Background(), []string{server.URL}, tt.cfg)
+			if tt.expectedErr == "" {
+				if err != nil {
+					t.Errorf("check() returned unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("check() should fail with %q", tt.expectedErr)
+			}
+			if !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Errorf("check() error = %q, want it to contain %q", err.Error(), tt.expectedErr)
+			}
+		})
+	}
+}
+
+func TestHealthChecker_EventuallyHealthy(t *testing.T) {
+	var count atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if count.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	c := &healthChecker{httpClient: server.Client()}
+	err := c.check(context.Background(), []string{server.URL}, healthCheckConfig{
+		Path:           "/",
+		ExpectedStatus: http.StatusOK,
+		Retries:        5,
+		Interval:       time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("check() returned unexpected error: %s", err)
+	}
+	if got := count.Load(); got != 3 {
+		t.Errorf("expected 3 attempts, got %d", got)
+	}
+}
+
+func TestHealthChecker_AttachesLastResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.Write([]byte("upstream unavailable"))
+	}))
+	defer server.Close()
+
+	c := &healthChecker{httpClient: server.Client()}
+	err := c.check(context.Background(), []string{server.URL}, healthCheckConfig{
+		Path:           "/",
+		ExpectedStatus: http.StatusOK,
+	})
+	if err == nil {
+		t.Fatal("check() should fail")
+	}
+	if !strings.Contains(err.Error(), "Status: 502") || !strings.Contains(err.Error(), "upstream unavailable") {
+		t.Errorf("error should contain the last response, got %q", err.Error())
+	}
+}
+
+func TestHealthCheckModel_ToConfig(t *testing.T) {
+	m := healthCheckModel{
+		Path:           types.StringUnknown(),
+		ExpectedStatus: types.Int64Unknown(),
+		BodyRegex:      types.StringUnknown(),
+		Headers:        types.MapUnknown(types.StringType),
+		Retries:        types.Int64Unknown(),
+		Interval:       types.StringUnknown(),
+	}
+	cfg, diags := m.toConfig(context.Background())
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics for unknown values: %v", diags)
+	}
+	if cfg.Path != defaultHealthCheckPath || cfg.Retries != defaultHealthCheckRetries || cfg.Interval != defaultHealthCheckInterval {
+		t.Errorf("cfg = %+v, want the defaults", cfg)
+	}
+
+	m = healthCheckModel{
+		BodyRegex: types.StringValue("("),
+		Headers:   types.MapNull(types.StringType),
+		Retries:   types.Int64Value(-1),
+		Interval:  types.StringValue("soon"),
+	}
+	_, diags = m.toConfig(context.Background())
+	if diags.ErrorsCount() != 3 {
+		t.Errorf("diagnostics = %v, want errors for body_regex, retries and interval", diags)
+	}
+
+	for _, interval := range []string{"0s", "-1s"} {
+		m = healthCheckModel{Headers: types.MapNull(types.StringType), Interval: types.StringValue(interval)}
+		if _, diags = m.toConfig(context.Background()); diags.ErrorsCount() != 1 {
+			t.Errorf("interval %s: diagnostics = %v, want an error", interval, diags)
+		}
+	}
+	for _, status := range []int64{0, 99, 600} {
+		m = healthCheckModel{Headers: types.MapNull(types.StringType), ExpectedStatus: types.Int64Value(status)}
+		if _, diags = m.toConfig(context.Background()); diags.ErrorsCount() != 1 {
+			t.Errorf("expected_status %d: diagnostics = %v, want an error", status, diags)
+		}
+	}
+}
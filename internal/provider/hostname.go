@@ -0,0 +1,249 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/attr/xattr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"golang.org/x/net/idna"
+)
+
+const (
+	maxHostnameLength      = 253
+	maxHostnameLabelLength = 63
+)
+
+// hostnameProfile converts internationalized hostnames to their ASCII form.
+// It maps the input like browsers do for lookups (e.g. lowercasing) and
+// enforces the STD3 rules, so that the only characters allowed in the result
+// are letters, digits and hyphens.
+var hostnameProfile = idna.New(
+	idna.MapForLookup(),
+	idna.Transitional(false),
+	idna.BidiRule(),
+	idna.StrictDomainName(true),
+	idna.ValidateLabels(true),
+	idna.VerifyDNSLength(false),
+)
+
+// normalizeHostname returns the canonical ASCII form of the hostname: IDN
+// labels are converted to punycode, the letters are lowercased and the
+// trailing dot of a fully qualified name is removed. A wildcard is only
+// accepted as the whole leftmost label, e.g. `*.example.com`.
+func normalizeHostname(hostname string) (string, error) {
+	name := strings.TrimSuffix(strings.TrimSpace(hostname), ".")
+	if name == "" {
+		return "", fmt.Errorf("hostname must not be empty")
+	}
+
+	wildcard := false
+	if rest, ok := strings.CutPrefix(name, "*."); ok {
+		wildcard = true
+		name = rest
+	}
+	if strings.Contains(name, "*") {
+		return "", fmt.Errorf("a wildcard is only allowed as the whole leftmost label, such as *.example.com")
+	}
+
+	ascii, err := hostnameProfile.ToASCII(name)
+	if err != nil {
+		return "", err
+	}
+
+	labels := strings.Split(ascii, ".")
+	for _, label := range labels {
+		if label == "" {
+			return "", fmt.Errorf("hostname must not contain empty labels")
+		}
+		if len(label) > maxHostnameLabelLength {
+			return "", fmt.Errorf("label %q is %d characters long, which exceeds the limit of %d", label, len(label), maxHostnameLabelLength)
+		}
+	}
+	if wildcard && len(labels) < 2 {
+		return "", fmt.Errorf("a wildcard must be followed by at least two labels, such as *.example.com")
+	}
+
+	if wildcard {
+		ascii = "*." + ascii
+	}
+	if len(ascii) > maxHostnameLength {
+		return "", fmt.Errorf("hostname is %d characters long, which exceeds the limit of %d", len(ascii), maxHostnameLength)
+	}
+
+	return ascii, nil
+}
+
+// hostnameASCII returns the normalized ASCII form of the hostname, or the
+// hostname as is if it can't be normalized.
+func hostnameASCII(hostname string) string {
+	if ascii, err := normalizeHostname(hostname); err == nil {
+		return ascii
+	}
+	return hostname
+}
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ basetypes.StringTypable                    = hostnameType{}
+	_ basetypes.StringValuableWithSemanticEquals = hostnameValue{}
+	_ xattr.ValidateableAttribute                = hostnameValue{}
+	_ planmodifier.String                        = hostnameASCIIPlanModifier{}
+)
+
+// hostnameType is a string type for hostnames. Values that normalize to the
+// same ASCII form are semantically equal, so `Example.COM.` and `example.com`
+// don't cause a diff.
+type hostnameType struct {
+	basetypes.StringType
+}
+
+func (t hostnameType) String() string {
+	return "hostnameType"
+}
+
+func (t hostnameType) Equal(o attr.Type) bool {
+	other, ok := o.(hostnameType)
+	if !ok {
+		return false
+	}
+	return t.StringType.Equal(other.StringType)
+}
+
+func (t hostnameType) ValueType(_ context.Context) attr.Value {
+	return hostnameValue{}
+}
+
+func (t hostnameType) ValueFromString(_ context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return hostnameValue{StringValue: in}, nil
+}
+
+func (t hostnameType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+	return hostnameValue{StringValue: stringValue}, nil
+}
+
+// hostnameValue is a value of hostnameType.
+type hostnameValue struct {
+	basetypes.StringValue
+}
+
+func newHostnameValue(value string) hostnameValue {
+	return hostnameValue{StringValue: basetypes.NewStringValue(value)}
+}
+
+func (v hostnameValue) Type(_ context.Context) attr.Type {
+	return hostnameType{}
+}
+
+func (v hostnameValue) Equal(o attr.Value) bool {
+	other, ok := o.(hostnameValue)
+	if !ok {
+		return false
+	}
+	return v.StringValue.Equal(other.StringValue)
+}
+
+// StringSemanticEquals returns true if both hostnames normalize to the same
+// ASCII form.
+func (v hostnameValue) StringSemanticEquals(_ context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	newValue, ok := newValuable.(hostnameValue)
+	if !ok {
+		diags.AddError(
+			"Semantic Equality Check Error",
+			fmt.Sprintf("Expected value type %T, got: %T. Please report this issue to the provider developers.", v, newValuable),
+		)
+		return false, diags
+	}
+
+	return hostnamesEqual(v.ValueString(), newValue.ValueString()), diags
+}
+
+// ValidateAttribute validates the hostname at plan time.
+func (v hostnameValue) ValidateAttribute(_ context.Context, req xattr.ValidateAttributeRequest, resp *xattr.ValidateAttributeResponse) {
+	if v.IsNull() || v.IsUnknown() {
+		return
+	}
+
+	if _, err := normalizeHostname(v.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Hostname",
+			fmt.Sprintf("%q is not a valid hostname: %s", v.ValueString(), err.Error()),
+		)
+	}
+}
+
+// hostnamesEqual reports whether two hostnames normalize to the same ASCII
+// form. Invalid hostnames are compared as is.
+func hostnamesEqual(a, b string) bool {
+	if a == b {
+		return true
+	}
+	na, errA := normalizeHostname(a)
+	nb, errB := normalizeHostname(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	return na == nb
+}
+
+// requiresReplaceIfHostnameChanged is a stringplanmodifier.RequiresReplaceIf
+// function that doesn't require a replacement when the planned hostname is
+// semantically equal to the prior state, e.g. `example.com` and
+// `Example.COM.`. Semantic equality is not applied when planning, so such a
+// change is planned as an in-place update instead.
+func requiresReplaceIfHostnameChanged(_ context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+	resp.RequiresReplace = !hostnamesEqual(req.StateValue.ValueString(), req.PlanValue.ValueString())
+}
+
+// hostnameASCIIPlanModifier plans the normalized ASCII form of the hostname
+// found at the given attribute.
+type hostnameASCIIPlanModifier struct {
+	hostnamePath path.Path
+}
+
+func (m hostnameASCIIPlanModifier) Description(_ context.Context) string {
+	return fmt.Sprintf("Sets the value to the normalized ASCII form of %s.", m.hostnamePath)
+}
+
+func (m hostnameASCIIPlanModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m hostnameASCIIPlanModifier) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	// Nothing to plan on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var hostname hostnameValue
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, m.hostnamePath, &hostname)...)
+	if resp.Diagnostics.HasError() || hostname.IsNull() || hostname.IsUnknown() {
+		return
+	}
+
+	ascii, err := normalizeHostname(hostname.ValueString())
+	if err != nil {
+		// Reported by the validation of the hostname attribute
+		return
+	}
+	resp.PlanValue = types.StringValue(ascii)
+}
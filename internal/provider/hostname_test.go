@@ -0,0 +1,176 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr/xattr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestNormalizeHostname(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "already normalized",
+			input:    "foo.example.com",
+			expected: "foo.example.com",
+		},
+		{
+			name:     "uppercase and trailing dot",
+			input:    "Example.COM.",
+			expected: "example.com",
+		},
+		{
+			name:     "IDN",
+			input:    "München.example.com",
+			expected: "xn--mnchen-3ya.example.com",
+		},
+		{
+			name:     "punycode is kept as is",
+			input:    "xn--mnchen-3ya.example.com",
+			expected: "xn--mnchen-3ya.example.com",
+		},
+		{
+			name:     "wildcard",
+			input:    "*.Example.com",
+			expected: "*.example.com",
+		},
+		{
+			name:     "label of 63 characters",
+			input:    strings.Repeat("a", 63) + ".com",
+			expected: strings.Repeat("a", 63) + ".com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeHostname(tt.input)
+			if err != nil {
+				t.Fatalf("normalizeHostname(%q) returned unexpected error: %s", tt.input, err)
+			}
+			if got != tt.expected {
+				t.Errorf("normalizeHostname(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNormalizeHostname_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "empty", input: ""},
+		{name: "only a dot", input: "."},
+		{name: "empty label", input: "foo..example.com"},
+		{name: "leading dot", input: ".example.com"},
+		{name: "label too long", input: strings.Repeat("a", 64) + ".com"},
+		{name: "hostname too long", input: strings.Repeat(strings.Repeat("a", 63)+".", 4) + "com"},
+		{name: "underscore", input: "foo_bar.example.com"},
+		{name: "space", input: "foo bar.example.com"},
+		{name: "leading hyphen", input: "-foo.example.com"},
+		{name: "wildcard not leftmost", input: "foo.*.example.com"},
+		{name: "partial wildcard", input: "foo*.example.com"},
+		{name: "wildcard on TLD", input: "*.com"},
+		{name: "URL", input: "https://example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := normalizeHostname(tt.input); err == nil {
+				t.Errorf("normalizeHostname(%q) = %q, want an error", tt.input, got)
+			}
+		})
+	}
+}
+
+func TestHostnameValue_SemanticEquals(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected bool
+	}{
+		{a: "example.com", b: "example.com", expected: true},
+		{a: "example.com", b: "Example.COM.", expected: true},
+		{a: "münchen.example.com", b: "xn--mnchen-3ya.example.com", expected: true},
+		{a: "example.com", b: "www.example.com", expected: false},
+		{a: "foo_bar.example.com", b: "FOO_BAR.example.com", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			got, diags := newHostnameValue(tt.a).StringSemanticEquals(context.Background(), newHostnameValue(tt.b))
+			if diags.HasError() {
+				t.Fatalf("StringSemanticEquals() returned unexpected diagnostics: %v", diags)
+			}
+			if got != tt.expected {
+				t.Errorf("StringSemanticEquals(%q, %q) = %t, want %t", tt.a, tt.b, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHostnameValue_ValidateAttribute(t *testing.T) {
+	req := xattr.ValidateAttributeRequest{Path: path.Root("domain")}
+
+	resp := &xattr.ValidateAttributeResponse{}
+	newHostnameValue("Example.COM.").ValidateAttribute(context.Background(), req, resp)
+	if resp.Diagnostics.HasError() {
+		t.Errorf("ValidateAttribute() returned unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	resp = &xattr.ValidateAttributeResponse{}
+	newHostnameValue("foo..example.com").ValidateAttribute(context.Background(), req, resp)
+	if !resp.Diagnostics.HasError() {
+		t.Error("ValidateAttribute() should fail for an invalid hostname")
+	}
+}
+
+func TestHostnameASCIIPlanModifier(t *testing.T) {
+	ctx := context.Background()
+
+	schemaResp := &resource.SchemaResponse{}
+	(&domainResource{}).Schema(ctx, resource.SchemaRequest{}, schemaResp)
+	objectType, ok := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	if !ok {
+		t.Fatal("schema type is not an object")
+	}
+
+	for _, tc := range []struct {
+		domain   any
+		expected types.String
+	}{
+		{"Bücher.Example.COM.", types.StringValue("xn--bcher-kva.example.com")},
+		{tftypes.UnknownValue, types.StringUnknown()},
+	} {
+		attrs := map[string]tftypes.Value{}
+		for name, typ := range objectType.AttributeTypes {
+			attrs[name] = tftypes.NewValue(typ, nil)
+		}
+		attrs["domain"] = tftypes.NewValue(tftypes.String, tc.domain)
+
+		req := planmodifier.StringRequest{
+			Path:      path.Root("domain_ascii"),
+			PlanValue: types.StringUnknown(),
+			Plan:      tfsdk.Plan{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, attrs)},
+		}
+		resp := &planmodifier.StringResponse{PlanValue: req.PlanValue}
+		hostnameASCIIPlanModifier{hostnamePath: path.Root("domain")}.PlanModifyString(ctx, req, resp)
+
+		if resp.Diagnostics.HasError() {
+			t.Errorf("domain %v: unexpected diagnostics: %v", tc.domain, resp.Diagnostics)
+		}
+		if !resp.PlanValue.Equal(tc.expected) {
+			t.Errorf("domain %v: plan value = %s, want %s", tc.domain, resp.PlanValue, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// httpClientConfig is the configuration of the HTTP client that the Deno
+// Deploy API client sends requests with.
+type httpClientConfig struct {
+	// CACertPEM and CACertFile are the PEM-encoded certificates of the
+	// certificate authorities to trust in addition to the ones of the system.
+	CACertPEM  string
+	CACertFile string
+	// ClientCert and ClientKey are the PEM-encoded certificate and private key
+	// presented to the server for mutual TLS.
+	ClientCert string
+	ClientKey  string
+	// ProxyURL is the proxy to send the requests through. If nil, the proxy
+	// is taken from the HTTPS_PROXY and NO_PROXY environment variables.
+	ProxyURL           *url.URL
+	InsecureSkipVerify bool
+	// RequestTimeout limits the time of each request including reading the
+	// response body. Zero means no timeout.
+	RequestTimeout time.Duration
+}
+
+// newHTTPClient returns an HTTP client configured with the given config.
+func (c httpClientConfig) newHTTPClient() (*http.Client, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// Only meant to be enabled in tests
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+
+	if c.CACertPEM != "" || c.CACertFile != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if c.CACertPEM != "" && !pool.AppendCertsFromPEM([]byte(c.CACertPEM)) {
+			return nil, errors.New("ca_cert_pem doesn't contain any valid PEM-encoded certificate")
+		}
+		if c.CACertFile != "" {
+			b, err := os.ReadFile(c.CACertFile)
+			if err != nil {
+				return nil, fmt.Errorf("could not read ca_cert_file: %w", err)
+			}
+			if !pool.AppendCertsFromPEM(b) {
+				return nil, fmt.Errorf("ca_cert_file %s doesn't contain any valid PEM-encoded certificate", c.CACertFile)
+			}
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if c.ClientCert != "" || c.ClientKey != "" {
+		if c.ClientCert == "" || c.ClientKey == "" {
+			return nil, errors.New("client_cert and client_key must be set together")
+		}
+		cert, err := tls.X509KeyPair([]byte(c.ClientCert), []byte(c.ClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("could not load client_cert and client_key: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport := &http.Transport{Proxy: http.ProxyFromEnvironment}
+	if defaultTransport, ok := http.DefaultTransport.(*http.Transport); ok {
+		transport = defaultTransport.Clone()
+	}
+	transport.TLSClientConfig = tlsConfig
+	if c.ProxyURL != nil {
+		transport.Proxy = http.ProxyURL(c.ProxyURL)
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   c.RequestTimeout,
+	}, nil
+}
+
+// userAgent returns the User-Agent header sent with every request to the
+// Deno Deploy API.
+func userAgent(providerVersion, terraformVersion string) string {
+	if terraformVersion == "" {
+		terraformVersion = "unknown"
+	}
+	return fmt.Sprintf("terraform-provider-deno/%s terraform/%s", providerVersion, terraformVersion)
+}
@@ -0,0 +1,263 @@
+package provider
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// serverCertPEM returns the PEM-encoded certificate of the TLS test server.
+func serverCertPEM(server *httptest.Server) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+}
+
+// newClientCert returns a self-signed client certificate and its private key,
+// both PEM-encoded.
+func newClientCert(t *testing.T) (*x509.Certificate, string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %s", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "terraform"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %s", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %s", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %s", err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return cert, string(certPEM), string(keyPEM)
+}
+
+// getWithConfig sends a GET request with the HTTP client built from the config.
+func getWithConfig(t *testing.T, config httpClientConfig, url string) (*http.Response, error) {
+	t.Helper()
+
+	c, err := config.newHTTPClient()
+	if err != nil {
+		t.Fatalf("newHTTPClient() returned unexpected error: %s", err)
+	}
+	resp, err := c.Get(url)
+	if err == nil {
+		resp.Body.Close()
+	}
+	return resp, err
+}
+
+func TestHTTPClient_CACert(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	if _, err := getWithConfig(t, httpClientConfig{}, server.URL); err == nil {
+		t.Fatal("the certificate of the test server should not be trusted by default")
+	}
+
+	if _, err := getWithConfig(t, httpClientConfig{CACertPEM: serverCertPEM(server)}, server.URL); err != nil {
+		t.Errorf("request with ca_cert_pem failed: %s", err)
+	}
+
+	file := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(file, []byte(serverCertPEM(server)), 0o600); err != nil {
+		t.Fatalf("failed to write CA file: %s", err)
+	}
+	if _, err := getWithConfig(t, httpClientConfig{CACertFile: file}, server.URL); err != nil {
+		t.Errorf("request with ca_cert_file failed: %s", err)
+	}
+
+	if _, err := getWithConfig(t, httpClientConfig{InsecureSkipVerify: true}, server.URL); err != nil {
+		t.Errorf("request with insecure_skip_verify failed: %s", err)
+	}
+}
+
+func TestHTTPClient_InvalidCACert(t *testing.T) {
+	for name, config := range map[string]httpClientConfig{
+		"pem":          {CACertPEM: "not a certificate"},
+		"missing file": {CACertFile: filepath.Join(t.TempDir(), "missing.pem")},
+		"partial mTLS": {ClientCert: "cert"},
+		"invalid mTLS": {ClientCert: "cert", ClientKey: "key"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := config.newHTTPClient(); err == nil {
+				t.Error("newHTTPClient() should fail")
+			}
+		})
+	}
+}
+
+func TestHTTPClient_ClientCert(t *testing.T) {
+	cert, certPEM, keyPEM := newClientCert(t)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(cert)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) == 0 || r.TLS.PeerCertificates[0].Subject.CommonName != "terraform" {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	server.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	if _, err := getWithConfig(t, httpClientConfig{CACertPEM: serverCertPEM(server)}, server.URL); err == nil {
+		t.Fatal("request without client certificate should fail")
+	}
+
+	resp, err := getWithConfig(t, httpClientConfig{CACertPEM: serverCertPEM(server), ClientCert: certPEM, ClientKey: keyPEM}, server.URL)
+	if err != nil {
+		t.Fatalf("request with client certificate failed: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+}
+
+func TestHTTPClient_Proxy(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.URL.String())
+	}))
+	defer proxy.Close()
+
+	proxyURL, err := url.Parse(proxy.URL)
+	if err != nil {
+		t.Fatalf("failed to parse proxy URL: %s", err)
+	}
+	if _, err := getWithConfig(t, httpClientConfig{ProxyURL: proxyURL}, "http://api.deno.invalid/v1/projects"); err != nil {
+		t.Fatalf("request through proxy failed: %s", err)
+	}
+	if len(proxied) != 1 || proxied[0] != "http://api.deno.invalid/v1/projects" {
+		t.Errorf("proxied = %v", proxied)
+	}
+}
+
+func TestHTTPClient_RequestTimeout(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+
+	_, err := getWithConfig(t, httpClientConfig{RequestTimeout: 10 * time.Millisecond}, server.URL)
+	if err == nil || !strings.Contains(err.Error(), "Client.Timeout exceeded") {
+		t.Errorf("err = %v, want a timeout", err)
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	var userAgents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgents = append(userAgents, r.UserAgent())
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	data := &deployProviderData{
+		host:              server.URL,
+		token:             "token",
+		rawOrganizationID: uuid.NewString(),
+		userAgent:         userAgent("1.2.3", "1.14.0"),
+	}
+	if diags := data.connect(); diags.HasError() {
+		t.Fatalf("connect() returned unexpected diagnostics: %v", diags)
+	}
+	if _, err := data.client.ListProjectsWithResponse(context.Background(), data.organizationID, nil); err != nil {
+		t.Fatalf("ListProjects() returned unexpected error: %s", err)
+	}
+
+	if len(userAgents) != 1 || userAgents[0] != "terraform-provider-deno/1.2.3 terraform/1.14.0" {
+		t.Errorf("User-Agent = %v", userAgents)
+	}
+}
+
+func TestNewHTTPClientConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        deployProviderModel
+		expectErr     bool
+		expectWarning bool
+	}{
+		{
+			name: "valid",
+			config: deployProviderModel{
+				ProxyURL:       types.StringValue("http://proxy.example.com:3128"),
+				RequestTimeout: types.StringValue("30s"),
+				ClientCert:     types.StringValue("cert"),
+				ClientKey:      types.StringValue("key"),
+			},
+		},
+		{
+			name:      "client cert without key",
+			config:    deployProviderModel{ClientCert: types.StringValue("cert")},
+			expectErr: true,
+		},
+		{
+			name:      "relative proxy URL",
+			config:    deployProviderModel{ProxyURL: types.StringValue("proxy.example.com")},
+			expectErr: true,
+		},
+		{
+			name:      "invalid timeout",
+			config:    deployProviderModel{RequestTimeout: types.StringValue("forever")},
+			expectErr: true,
+		},
+		{
+			name:          "insecure",
+			config:        deployProviderModel{InsecureSkipVerify: types.BoolValue(true)},
+			expectWarning: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpConfig, diags := newHTTPClientConfig(tt.config)
+			if diags.HasError() != tt.expectErr {
+				t.Fatalf("diagnostics = %v, want error: %t", diags, tt.expectErr)
+			}
+			if (diags.WarningsCount() > 0) != tt.expectWarning {
+				t.Errorf("diagnostics = %v, want warning: %t", diags, tt.expectWarning)
+			}
+			if tt.name == "valid" && (httpConfig.RequestTimeout != 30*time.Second || httpConfig.ProxyURL.Host != "proxy.example.com:3128") {
+				t.Errorf("httpConfig = %+v", httpConfig)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// checkImportOrganization checks that the organization of the identity being
+// imported, if any, is the organization of the provider. A provider is
+// configured for a single organization, so objects of other organizations
+// have to be imported with a provider configured for them.
+func checkImportOrganization(ctx context.Context, req resource.ImportStateRequest, organizationID uuid.UUID) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if req.ID != "" || req.Identity == nil || req.Identity.Raw.IsNull() {
+		return diags
+	}
+
+	var identityOrganizationID types.String
+	diags.Append(req.Identity.GetAttribute(ctx, path.Root("organization_id"), &identityOrganizationID)...)
+	if diags.HasError() || identityOrganizationID.IsNull() {
+		return diags
+	}
+
+	if !strings.EqualFold(identityOrganizationID.ValueString(), organizationID.String()) {
+		diags.AddAttributeError(
+			path.Root("organization_id"),
+			"Organization Mismatch",
+			fmt.Sprintf("The object to import belongs to organization %s, but the provider is configured for organization %s. Import it with a provider configured for organization %s.", identityOrganizationID.ValueString(), organizationID, identityOrganizationID.ValueString()),
+		)
+	}
+	return diags
+}
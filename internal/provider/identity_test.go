@@ -0,0 +1,161 @@
+package provider
+
+import (
+	"context"
+	"terraform-provider-deno/client"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// importResource calls ImportState on the resource, either with the import ID
+// or, if it is empty, with an identity holding the given attribute values.
+func importResource(t *testing.T, r resource.ResourceWithImportState, id string, identity map[string]any) *resource.ImportStateResponse {
+	t.Helper()
+	ctx := context.Background()
+
+	schemaResp := &resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, schemaResp)
+	identitySchemaResp := &resource.IdentitySchemaResponse{}
+	r.(resource.ResourceWithIdentity).IdentitySchema(ctx, resource.IdentitySchemaRequest{}, identitySchemaResp)
+
+	identityType, ok := identitySchemaResp.IdentitySchema.Type().TerraformType(ctx).(tftypes.Object)
+	if !ok {
+		t.Fatal("identity schema type is not an object")
+	}
+	identityRaw := tftypes.NewValue(identityType, nil)
+	if id == "" {
+		attrs := map[string]tftypes.Value{}
+		for name, typ := range identityType.AttributeTypes {
+			attrs[name] = tftypes.NewValue(typ, identity[name])
+		}
+		identityRaw = tftypes.NewValue(identityType, attrs)
+	}
+
+	req := resource.ImportStateRequest{
+		ID:       id,
+		Identity: &tfsdk.ResourceIdentity{Schema: identitySchemaResp.IdentitySchema, Raw: identityRaw},
+	}
+	resp := &resource.ImportStateResponse{
+		State: tfsdk.State{
+			Schema: schemaResp.Schema,
+			Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+		},
+		Identity: &tfsdk.ResourceIdentity{Schema: identitySchemaResp.IdentitySchema, Raw: identityRaw.Copy()},
+	}
+	r.ImportState(ctx, req, resp)
+	return resp
+}
+
+// importedAttribute returns the value of a string attribute of the imported
+// state.
+func importedAttribute(t *testing.T, resp *resource.ImportStateResponse, name string) string {
+	t.Helper()
+
+	var v types.String
+	if diags := resp.State.GetAttribute(context.Background(), path.Root(name), &v); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	return v.ValueString()
+}
+
+func TestProjectResource_ImportState(t *testing.T) {
+	organizationID := uuid.New()
+	projectID := uuid.NewString()
+	r := &projectResource{organizationID: organizationID}
+
+	t.Run("ID", func(t *testing.T) {
+		resp := importResource(t, r, projectID, nil)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+		}
+		if got := importedAttribute(t, resp, "id"); got != projectID {
+			t.Errorf("id = %s, want %s", got, projectID)
+		}
+	})
+
+	t.Run("identity", func(t *testing.T) {
+		resp := importResource(t, r, "", map[string]any{"id": projectID})
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+		}
+		if got := importedAttribute(t, resp, "id"); got != projectID {
+			t.Errorf("id = %s, want %s", got, projectID)
+		}
+	})
+
+	t.Run("identity of the same organization", func(t *testing.T) {
+		resp := importResource(t, r, "", map[string]any{"organization_id": organizationID.String(), "id": projectID})
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+		}
+	})
+
+	t.Run("identity of another organization", func(t *testing.T) {
+		resp := importResource(t, r, "", map[string]any{"organization_id": uuid.NewString(), "id": projectID})
+		if !resp.Diagnostics.HasError() {
+			t.Fatal("ImportState() should fail for another organization")
+		}
+		if resp.Diagnostics[0].Summary() != "Organization Mismatch" {
+			t.Errorf("unexpected diagnostics: %v", resp.Diagnostics)
+		}
+	})
+}
+
+func TestDomainResource_ImportState(t *testing.T) {
+	organizationID := uuid.New()
+	domainID := uuid.New()
+	fake := &fakeListClient{
+		domains: []client.Domain{
+			{Id: uuid.New(), OrganizationId: organizationID, Domain: "example.com"},
+			{Id: domainID, OrganizationId: organizationID, Domain: "xn--mnchen-3ya.example.com"},
+		},
+	}
+	r := &domainResource{client: fake, organizationID: organizationID}
+
+	tests := []struct {
+		name     string
+		id       string
+		identity map[string]any
+	}{
+		{name: "domain ID", id: domainID.String()},
+		{name: "domain name", id: "München.example.com"},
+		{name: "identity", identity: map[string]any{"domain": "xn--mnchen-3ya.example.com"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := importResource(t, r, tt.id, tt.identity)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+			if got := importedAttribute(t, resp, "id"); got != domainID.String() {
+				t.Errorf("id = %s, want %s", got, domainID)
+			}
+		})
+	}
+
+	t.Run("unknown domain", func(t *testing.T) {
+		resp := importResource(t, r, "", map[string]any{"domain": "unknown.example.com"})
+		if !resp.Diagnostics.HasError() {
+			t.Fatal("ImportState() should fail for an unknown domain")
+		}
+	})
+}
+
+func TestDeploymentResource_ImportState(t *testing.T) {
+	r := &deploymentResource{organizationID: uuid.New()}
+
+	resp := importResource(t, r, "", map[string]any{"deployment_id": "abcdefghijkl"})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	if got := importedAttribute(t, resp, "deployment_id"); got != "abcdefghijkl" {
+		t.Errorf("deployment_id = %s, want abcdefghijkl", got)
+	}
+}
@@ -0,0 +1,77 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ function.Function = &inlineAssetFunction{}
+
+func NewInlineAssetFunction() function.Function {
+	return &inlineAssetFunction{}
+}
+
+type inlineAssetFunction struct{}
+
+func (f *inlineAssetFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "inline_asset"
+}
+
+func (f *inlineAssetFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Build an inlined file asset",
+		Description: "Returns a `file` asset with the given content inlined, which can be used as a value of the `assets` attribute of `deno_deployment`. The `git_sha1` of the asset is computed over the decoded content.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "content",
+				Description: "The content of the file.",
+			},
+			function.StringParameter{
+				Name:        "encoding",
+				Description: "The encoding of `content`: `utf-8` or `base64`.",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: assetAttrTypes,
+		},
+	}
+}
+
+func (f *inlineAssetFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var content, encoding string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &content, &encoding))
+	if resp.Error != nil {
+		return
+	}
+
+	var b []byte
+	switch encoding {
+	case "utf-8":
+		b = []byte(content)
+	case "base64":
+		decoded, err := base64.StdEncoding.DecodeString(content)
+		if err != nil {
+			resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("Could not decode the content as base64: %s", err.Error()))
+			return
+		}
+		b = decoded
+	default:
+		resp.Error = function.NewArgumentFuncError(1, fmt.Sprintf("Unsupported encoding %q. Possible values are `utf-8` and `base64`.", encoding))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, asset{
+		Kind:              types.StringValue("file"),
+		LocalFilePath:     types.StringNull(),
+		RuntimeTargetPath: types.StringNull(),
+		GitSHA1:           types.StringValue(calculateGitSha1(b)),
+		Content:           types.StringValue(content),
+		Encoding:          types.StringValue(encoding),
+	}))
+}
@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+func runInlineAssetFunction(t *testing.T, content, encoding string) (asset, *function.FuncError) {
+	t.Helper()
+
+	req := function.RunRequest{
+		Arguments: function.NewArgumentsData([]attr.Value{types.StringValue(content), types.StringValue(encoding)}),
+	}
+	resp := &function.RunResponse{Result: function.NewResultData(types.ObjectUnknown(assetAttrTypes))}
+	NewInlineAssetFunction().Run(context.Background(), req, resp)
+	if resp.Error != nil {
+		return asset{}, resp.Error
+	}
+
+	obj, ok := resp.Result.Value().(types.Object)
+	if !ok {
+		t.Fatalf("Run() returned %T, want types.Object", resp.Result.Value())
+	}
+	var a asset
+	if diags := obj.As(context.Background(), &a, basetypes.ObjectAsOptions{}); diags.HasError() {
+		t.Fatalf("result is not an asset: %v", diags)
+	}
+	return a, nil
+}
+
+func TestInlineAssetFunction(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		encoding string
+		gitSHA1  string
+	}{
+		{
+			name:     "utf-8",
+			content:  "hello\n",
+			encoding: "utf-8",
+			gitSHA1:  "ce013625030ba8dba906f756967f9e9ca394464a",
+		},
+		{
+			name:     "base64",
+			content:  "aGVsbG8K",
+			encoding: "base64",
+			gitSHA1:  "ce013625030ba8dba906f756967f9e9ca394464a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := runInlineAssetFunction(t, tt.content, tt.encoding)
+			if err != nil {
+				t.Fatalf("inline_asset() returned unexpected error: %s", err)
+			}
+			if a.Kind.ValueString() != "file" {
+				t.Errorf("kind = %s, want file", a.Kind)
+			}
+			if a.Content.ValueString() != tt.content || a.Encoding.ValueString() != tt.encoding {
+				t.Errorf("content = %s (%s), want %q (%s)", a.Content, a.Encoding, tt.content, tt.encoding)
+			}
+			if a.GitSHA1.ValueString() != tt.gitSHA1 {
+				t.Errorf("git_sha1 = %s, want %s", a.GitSHA1, tt.gitSHA1)
+			}
+			if !a.LocalFilePath.IsNull() || !a.RuntimeTargetPath.IsNull() {
+				t.Errorf("content_source_path and target should be null, got %s and %s", a.LocalFilePath, a.RuntimeTargetPath)
+			}
+
+			// The asset must produce the same digest as the deployment
+			// computes for inlined content
+			d, ok, digestErr := digestAsset(a)
+			if digestErr != nil || !ok || d.gitSHA1 != tt.gitSHA1 {
+				t.Errorf("digestAsset() = %+v, %t, %v, want git SHA1 %s", d, ok, digestErr, tt.gitSHA1)
+			}
+		})
+	}
+}
+
+func TestInlineAssetFunction_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		encoding string
+		argument int64
+	}{
+		{name: "invalid base64", content: "not base64!", encoding: "base64", argument: 0},
+		{name: "unsupported encoding", content: "hello", encoding: "utf-16", argument: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := runInlineAssetFunction(t, tt.content, tt.encoding)
+			if err == nil {
+				t.Fatal("inline_asset() should fail")
+			}
+			if err.FunctionArgument == nil || *err.FunctionArgument != tt.argument {
+				t.Errorf("error should point at argument %d, got %v", tt.argument, err.FunctionArgument)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package provider
+
+import (
+	"context"
+	"iter"
+)
+
+// listPageSize is the number of items requested per page from the list
+// endpoints of the API.
+const listPageSize = 100
+
+// fetchPageFunc returns the items of the given page, numbered from 1.
+type fetchPageFunc[T any] func(ctx context.Context, page, limit int) ([]T, error)
+
+// listPages yields the items of every page in order, requesting the next page
+// only once the items of the previous one have been consumed. It stops after
+// the first page with fewer than listPageSize items, or after yielding an
+// error.
+func listPages[T any](ctx context.Context, fetch fetchPageFunc[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for page := 1; ; page++ {
+			items, err := fetch(ctx, page, listPageSize)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if len(items) < listPageSize {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-deno/client"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ action.Action              = &pointDomainAction{}
+	_ action.ActionWithConfigure = &pointDomainAction{}
+)
+
+// NewPointDomainAction is a helper function to simplify the provider implementation.
+func NewPointDomainAction() action.Action {
+	return &pointDomainAction{}
+}
+
+// pointDomainAction is the action implementation.
+type pointDomainAction struct {
+	client         client.ClientWithResponsesInterface
+	organizationID uuid.UUID
+}
+
+// pointDomainActionModel maps the action schema data.
+type pointDomainActionModel struct {
+	DomainID     types.String `tfsdk:"domain_id"`
+	DeploymentID types.String `tfsdk:"deployment_id"`
+}
+
+// Metadata returns the action type name.
+func (a *pointDomainAction) Metadata(_ context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_point_domain"
+}
+
+// Schema defines the schema for the action.
+func (a *pointDomainAction) Schema(_ context.Context, _ action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: `
+Points a custom domain to a deployment, so that the domain serves that deployment.
+
+This is useful to switch the traffic between deployments without redeploying, e.g. to roll back with ` + "`terraform apply -invoke=action.deno_point_domain.<name>`" + `. The domain must be verified and have certificates provisioned. Omitting deployment_id detaches the domain from the deployment it points to.
+		`,
+		Attributes: map[string]schema.Attribute{
+			"domain_id": schema.StringAttribute{
+				Required:    true,
+				Description: "The ID of the domain to point.",
+			},
+			"deployment_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "The ID of the deployment to point the domain to. If omitted, the domain is detached from its deployment.",
+			},
+		},
+	}
+}
+
+// Invoke updates the association of the domain, streaming the progress to
+// Terraform.
+func (a *pointDomainAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var config pointDomainActionModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	domainID, err := uuid.Parse(config.DomainID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Unable to Point Domain %s", config.DomainID),
+			fmt.Sprintf("Could not parse domain ID %s: %s", config.DomainID, err.Error()),
+		)
+		return
+	}
+
+	ctx = withProgress(ctx, resp.SendProgress)
+
+	var deploymentID *client.DeploymentId
+	if config.DeploymentID.IsNull() {
+		reportProgress(ctx, "Detaching domain %s from its deployment", domainID)
+	} else {
+		deploymentID = config.DeploymentID.ValueStringPointer()
+		reportProgress(ctx, "Pointing domain %s to deployment %s", domainID, *deploymentID)
+	}
+
+	result, err := a.client.UpdateDomainAssociationWithResponse(ctx, domainID, client.UpdateDomainAssociationJSONRequestBody{
+		DeploymentId: deploymentID,
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Unable to Point Domain %s", domainID),
+			fmt.Sprintf("API returned error: %s", err.Error()),
+		)
+		return
+	}
+	if client.RespIsError(result) {
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Unable to Point Domain %s", domainID),
+			client.APIErrorDetail(result.HTTPResponse, result.Body),
+		)
+		return
+	}
+
+	tflog.Info(ctx, "Updated domain association", map[string]any{
+		"domain_id":     domainID.String(),
+		"deployment_id": config.DeploymentID.ValueString(),
+	})
+	reportProgress(ctx, "Domain %s is updated", domainID)
+}
+
+// Configure adds the provider configured client to the action.
+func (a *pointDomainAction) Configure(_ context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*deployProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Action Configure Type",
+			fmt.Sprintf("Expected *deployProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	resp.Diagnostics.Append(providerData.connect()...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	a.client = providerData.client
+	a.organizationID = providerData.organizationID
+}
@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestPointDomainAction(t *testing.T) {
+	domainID := uuid.New()
+
+	t.Run("point", func(t *testing.T) {
+		fake := &fakeAssociationClient{}
+		messages, diags := invokeAction(t, &pointDomainAction{client: fake}, map[string]any{
+			"domain_id":     domainID.String(),
+			"deployment_id": "abcdefghijkl",
+		})
+		if diags.HasError() {
+			t.Fatalf("Invoke() returned unexpected diagnostics: %v", diags)
+		}
+		if deploymentID := fake.updates[domainID]; deploymentID == nil || *deploymentID != "abcdefghijkl" {
+			t.Errorf("updates = %v, want the domain to point to abcdefghijkl", fake.updates)
+		}
+		if len(messages) != 2 || messages[0] != "Pointing domain "+domainID.String()+" to deployment abcdefghijkl" {
+			t.Errorf("messages = %q", messages)
+		}
+	})
+
+	t.Run("detach", func(t *testing.T) {
+		fake := &fakeAssociationClient{}
+		_, diags := invokeAction(t, &pointDomainAction{client: fake}, map[string]any{
+			"domain_id": domainID.String(),
+		})
+		if diags.HasError() {
+			t.Fatalf("Invoke() returned unexpected diagnostics: %v", diags)
+		}
+		if deploymentID, ok := fake.updates[domainID]; !ok || deploymentID != nil {
+			t.Errorf("updates = %v, want the domain to be detached", fake.updates)
+		}
+	})
+
+	t.Run("invalid domain ID", func(t *testing.T) {
+		fake := &fakeAssociationClient{}
+		_, diags := invokeAction(t, &pointDomainAction{client: fake}, map[string]any{
+			"domain_id": "not-a-uuid",
+		})
+		if !diags.HasError() || len(fake.updates) != 0 {
+			t.Errorf("diagnostics = %v, updates = %v, want an error without updates", diags, fake.updates)
+		}
+	})
+}
@@ -0,0 +1,192 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+const (
+	defaultPollInterval      = 5 * time.Second
+	defaultMaxPollInterval   = time.Minute
+	defaultBackoffMultiplier = 1.5
+
+	// pollJitter is the fraction by which each delay is randomly shortened
+	// or lengthened, so that many resources polling at once spread out.
+	pollJitter = 0.1
+)
+
+// clock abstracts time so that the poller can run on a fake clock in tests.
+type clock interface {
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// poller calls a function until it reports completion. The first attempt is
+// made immediately, then the delay between attempts starts at Interval and is
+// multiplied by Multiplier after each attempt, up to MaxInterval.
+type poller struct {
+	Interval    time.Duration
+	MaxInterval time.Duration
+	Multiplier  float64
+	Jitter      float64
+	// MaxAttempts is the maximum number of attempts, or 0 for no limit other
+	// than the deadline of the context.
+	MaxAttempts int
+
+	clock clock
+	// random returns a number in [0, 1) and is used to apply the jitter.
+	random func() float64
+}
+
+// pollAttemptsExhaustedError is returned when the poller gave up after
+// MaxAttempts attempts.
+type pollAttemptsExhaustedError struct {
+	Attempts int
+}
+
+func (e *pollAttemptsExhaustedError) Error() string {
+	return fmt.Sprintf("gave up after %d attempt(s)", e.Attempts)
+}
+
+// poll calls fn until it returns true or an error. It returns the error
+// returned by fn, the error of the context if it is done before completion,
+// or *pollAttemptsExhaustedError.
+func (p *poller) poll(ctx context.Context, fn func(ctx context.Context, attempt int) (bool, error)) error {
+	c := p.clock
+	if c == nil {
+		c = realClock{}
+	}
+
+	delay := p.Interval
+	for attempt := 1; ; attempt++ {
+		done, err := p.attempt(ctx, attempt, fn)
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+		if p.MaxAttempts > 0 && attempt >= p.MaxAttempts {
+			return &pollAttemptsExhaustedError{Attempts: attempt}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-c.After(p.jittered(delay)):
+		}
+
+		delay = p.next(delay)
+	}
+}
+
+// attempt calls fn within a span of the poll iteration. The API responses
+// are never served from the read cache, as fn waits for them to change.
+func (p *poller) attempt(ctx context.Context, attempt int, fn func(ctx context.Context, attempt int) (bool, error)) (bool, error) {
+	ctx, span := startSpan(withFreshReads(ctx), "poll", attribute.Int("attempt", attempt))
+	defer span.End()
+
+	done, err := fn(ctx, attempt)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.SetAttributes(attribute.Bool("done", done))
+	return done, err
+}
+
+// next returns the delay that follows the given one.
+func (p *poller) next(delay time.Duration) time.Duration {
+	if p.Multiplier > 1 {
+		delay = time.Duration(float64(delay) * p.Multiplier)
+	}
+	if p.MaxInterval > 0 && delay > p.MaxInterval {
+		delay = p.MaxInterval
+	}
+	return delay
+}
+
+func (p *poller) jittered(delay time.Duration) time.Duration {
+	if p.Jitter <= 0 {
+		return delay
+	}
+	random := p.random
+	if random == nil {
+		random = rand.Float64
+	}
+	return time.Duration(float64(delay) * (1 + p.Jitter*(2*random()-1)))
+}
+
+// newPollerFromAttributes returns a poller configured with the poll_interval,
+// max_poll_interval and backoff_multiplier attributes, applying the defaults
+// to the omitted ones.
+func newPollerFromAttributes(pollInterval, maxPollInterval types.String, backoffMultiplier types.Float64) (*poller, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	p := &poller{
+		Interval:    defaultPollInterval,
+		MaxInterval: defaultMaxPollInterval,
+		Multiplier:  defaultBackoffMultiplier,
+		Jitter:      pollJitter,
+	}
+
+	parse := func(attribute string, value types.String) time.Duration {
+		d, err := time.ParseDuration(value.ValueString())
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root(attribute),
+				"Invalid Polling Interval",
+				fmt.Sprintf("Could not parse %q as a duration: %s", value.ValueString(), err.Error()),
+			)
+		} else if d <= 0 {
+			diags.AddAttributeError(
+				path.Root(attribute),
+				"Invalid Polling Interval",
+				fmt.Sprintf("The interval must be positive, got %s", value.ValueString()),
+			)
+		}
+		return d
+	}
+
+	if !pollInterval.IsNull() && !pollInterval.IsUnknown() {
+		p.Interval = parse("poll_interval", pollInterval)
+	}
+	if !maxPollInterval.IsNull() && !maxPollInterval.IsUnknown() {
+		p.MaxInterval = parse("max_poll_interval", maxPollInterval)
+	} else if p.Interval > p.MaxInterval {
+		p.MaxInterval = p.Interval
+	}
+	if !backoffMultiplier.IsNull() && !backoffMultiplier.IsUnknown() {
+		p.Multiplier = backoffMultiplier.ValueFloat64()
+		if p.Multiplier < 1 {
+			diags.AddAttributeError(
+				path.Root("backoff_multiplier"),
+				"Invalid Backoff Multiplier",
+				fmt.Sprintf("The multiplier must be at least 1, got %g", p.Multiplier),
+			)
+		}
+	}
+
+	if !diags.HasError() && p.MaxInterval < p.Interval {
+		diags.AddAttributeError(
+			path.Root("max_poll_interval"),
+			"Invalid Polling Interval",
+			fmt.Sprintf("max_poll_interval (%s) must not be shorter than poll_interval (%s)", p.MaxInterval, p.Interval),
+		)
+	}
+
+	return p, diags
+}
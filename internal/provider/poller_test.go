@@ -0,0 +1,196 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// fakeClock fires every timer immediately and records the requested delays.
+type fakeClock struct {
+	now    time.Time
+	delays []time.Duration
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.delays = append(c.delays, d)
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func TestPoller_Backoff(t *testing.T) {
+	clock := &fakeClock{}
+	p := &poller{
+		Interval:    time.Second,
+		MaxInterval: 5 * time.Second,
+		Multiplier:  2,
+		clock:       clock,
+	}
+
+	var attempts []int
+	err := p.poll(context.Background(), func(_ context.Context, attempt int) (bool, error) {
+		attempts = append(attempts, attempt)
+		if attempt == 1 && len(clock.delays) != 0 {
+			t.Errorf("first attempt should be made immediately, waited %v", clock.delays)
+		}
+		return attempt == 6, nil
+	})
+	if err != nil {
+		t.Fatalf("poll() returned unexpected error: %s", err)
+	}
+
+	if !reflect.DeepEqual(attempts, []int{1, 2, 3, 4, 5, 6}) {
+		t.Errorf("attempts = %v", attempts)
+	}
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	if !reflect.DeepEqual(clock.delays, expected) {
+		t.Errorf("delays = %v, want %v", clock.delays, expected)
+	}
+}
+
+func TestPoller_ConstantInterval(t *testing.T) {
+	clock := &fakeClock{}
+	p := &poller{Interval: 3 * time.Second, clock: clock}
+
+	_ = p.poll(context.Background(), func(_ context.Context, attempt int) (bool, error) {
+		return attempt == 4, nil
+	})
+
+	expected := []time.Duration{3 * time.Second, 3 * time.Second, 3 * time.Second}
+	if !reflect.DeepEqual(clock.delays, expected) {
+		t.Errorf("delays = %v, want %v", clock.delays, expected)
+	}
+}
+
+func TestPoller_Jitter(t *testing.T) {
+	randoms := []float64{0, 0.5, 0.999999}
+	clock := &fakeClock{}
+	p := &poller{
+		Interval: 10 * time.Second,
+		Jitter:   0.1,
+		clock:    clock,
+		random: func() float64 {
+			r := randoms[0]
+			randoms = randoms[1:]
+			return r
+		},
+	}
+
+	_ = p.poll(context.Background(), func(_ context.Context, attempt int) (bool, error) {
+		return attempt == 4, nil
+	})
+
+	if len(clock.delays) != 3 {
+		t.Fatalf("delays = %v, want 3 delays", clock.delays)
+	}
+	if clock.delays[0] != 9*time.Second {
+		t.Errorf("delays[0] = %s, want 9s", clock.delays[0])
+	}
+	if clock.delays[1] != 10*time.Second {
+		t.Errorf("delays[1] = %s, want 10s", clock.delays[1])
+	}
+	if clock.delays[2] <= 10*time.Second || clock.delays[2] > 11*time.Second {
+		t.Errorf("delays[2] = %s, want within (10s, 11s]", clock.delays[2])
+	}
+}
+
+func TestPoller_MaxAttempts(t *testing.T) {
+	clock := &fakeClock{}
+	p := &poller{Interval: time.Second, MaxAttempts: 3, clock: clock}
+
+	calls := 0
+	err := p.poll(context.Background(), func(_ context.Context, _ int) (bool, error) {
+		calls++
+		return false, nil
+	})
+
+	var exhausted *pollAttemptsExhaustedError
+	if !errors.As(err, &exhausted) || exhausted.Attempts != 3 {
+		t.Fatalf("poll() error = %v, want exhausted after 3 attempts", err)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+	if len(clock.delays) != 2 {
+		t.Errorf("delays = %v, want 2 delays", clock.delays)
+	}
+}
+
+func TestPoller_Error(t *testing.T) {
+	p := &poller{Interval: time.Second, clock: &fakeClock{}}
+	want := errors.New("boom")
+
+	err := p.poll(context.Background(), func(_ context.Context, attempt int) (bool, error) {
+		if attempt == 2 {
+			return false, want
+		}
+		return false, nil
+	})
+	if !errors.Is(err, want) {
+		t.Errorf("poll() error = %v, want %v", err, want)
+	}
+}
+
+func TestPoller_ContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	// The clock never fires, so only the cancellation ends the wait
+	p := &poller{Interval: time.Hour, clock: blockingClock{}}
+
+	err := p.poll(ctx, func(_ context.Context, _ int) (bool, error) {
+		cancel()
+		return false, nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("poll() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+type blockingClock struct{}
+
+func (blockingClock) After(time.Duration) <-chan time.Time {
+	return make(chan time.Time)
+}
+
+func TestNewPollerFromAttributes(t *testing.T) {
+	p, diags := newPollerFromAttributes(types.StringNull(), types.StringNull(), types.Float64Null())
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if p.Interval != defaultPollInterval || p.MaxInterval != defaultMaxPollInterval || p.Multiplier != defaultBackoffMultiplier {
+		t.Errorf("defaults = %s %s %g", p.Interval, p.MaxInterval, p.Multiplier)
+	}
+
+	// A long interval raises the default maximum
+	p, diags = newPollerFromAttributes(types.StringValue("2m"), types.StringNull(), types.Float64Value(1))
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if p.Interval != 2*time.Minute || p.MaxInterval != 2*time.Minute || p.Multiplier != 1 {
+		t.Errorf("poller = %s %s %g", p.Interval, p.MaxInterval, p.Multiplier)
+	}
+
+	invalid := []struct {
+		name                  string
+		interval, maxInterval types.String
+		multiplier            types.Float64
+	}{
+		{name: "unparsable interval", interval: types.StringValue("soon"), maxInterval: types.StringNull(), multiplier: types.Float64Null()},
+		{name: "negative interval", interval: types.StringValue("-1s"), maxInterval: types.StringNull(), multiplier: types.Float64Null()},
+		{name: "max shorter than interval", interval: types.StringValue("10s"), maxInterval: types.StringValue("5s"), multiplier: types.Float64Null()},
+		{name: "multiplier below 1", interval: types.StringNull(), maxInterval: types.StringNull(), multiplier: types.Float64Value(0.5)},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			_, diags := newPollerFromAttributes(tt.interval, tt.maxInterval, tt.multiplier)
+			if !diags.HasError() {
+				t.Error("newPollerFromAttributes() should fail")
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"terraform-provider-deno/client"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ list.ListResource              = &projectListResource{}
+	_ list.ListResourceWithConfigure = &projectListResource{}
+)
+
+// NewProjectListResource is a helper function to simplify the provider implementation.
+func NewProjectListResource() list.ListResource {
+	return &projectListResource{}
+}
+
+// projectListResource lists the projects of the organization.
+type projectListResource struct {
+	client         client.ClientWithResponsesInterface
+	organizationID uuid.UUID
+}
+
+// projectListResourceModel maps the list resource schema data.
+type projectListResourceModel struct {
+	NamePrefix types.String `tfsdk:"name_prefix"`
+}
+
+// Metadata returns the resource type name.
+func (r *projectListResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_project"
+}
+
+// ListResourceConfigSchema defines the filters of the list resource.
+func (r *projectListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the projects of the organization.",
+		Attributes: map[string]schema.Attribute{
+			"name_prefix": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only list the projects whose name starts with this prefix.",
+			},
+		},
+	}
+}
+
+// List streams the projects matching the filters.
+func (r *projectListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var config projectListResourceModel
+	diags := req.Config.Get(ctx, &config)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	stream.Results = func(push func(list.ListResult) bool) {
+		var count int64
+		for project, err := range listPages(ctx, r.fetchPage) {
+			if err != nil {
+				var diags diag.Diagnostics
+				diags.AddError("Unable to List Projects", err.Error())
+				push(list.ListResult{Diagnostics: diags})
+				return
+			}
+			if !strings.HasPrefix(project.Name, config.NamePrefix.ValueString()) {
+				continue
+			}
+
+			result := req.NewListResult(ctx)
+			result.DisplayName = project.Name
+			result.Diagnostics.Append(result.Identity.Set(ctx, newProjectResourceIdentity(r.organizationID, types.StringValue(project.Id.String())))...)
+			if req.IncludeResource {
+				result.Diagnostics.Append(result.Resource.Set(ctx, projectResourceModel{
+					ID:        types.StringValue(project.Id.String()),
+					Name:      types.StringValue(project.Name),
+					CreatedAt: types.StringValue(project.CreatedAt.Format(time.RFC3339)),
+					UpdatedAt: types.StringValue(project.UpdatedAt.Format(time.RFC3339)),
+				})...)
+			}
+
+			if !push(result) {
+				return
+			}
+			count++
+			if req.Limit > 0 && count >= req.Limit {
+				return
+			}
+		}
+	}
+}
+
+func (r *projectListResource) fetchPage(ctx context.Context, page, limit int) ([]client.Project, error) {
+	res, err := r.client.ListProjectsWithResponse(ctx, r.organizationID, &client.ListProjectsParams{
+		Page:  &page,
+		Limit: &limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if client.RespIsError(res) {
+		return nil, fmt.Errorf("%s", client.APIErrorDetail(res.HTTPResponse, res.Body))
+	}
+	if res.JSON200 == nil {
+		return nil, nil
+	}
+	return *res.JSON200, nil
+}
+
+// Configure adds the provider configured client to the list resource.
+func (r *projectListResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*deployProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected List Resource Configure Type",
+			fmt.Sprintf("Expected *deployProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	resp.Diagnostics.Append(providerData.connect()...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.client = providerData.client
+	r.organizationID = providerData.organizationID
+}
@@ -0,0 +1,177 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"terraform-provider-deno/client"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// fakeListClient serves paginated projects, domains and deployments.
+type fakeListClient struct {
+	client.ClientWithResponsesInterface
+
+	projects    []client.Project
+	domains     []client.Domain
+	deployments []client.Deployment
+	pages       int
+}
+
+// paginate returns the items of the requested page.
+func paginate[T any](items []T, page, limit *int) []T {
+	start := (*page - 1) * *limit
+	if start >= len(items) {
+		return []T{}
+	}
+	return items[start:min(start+*limit, len(items))]
+}
+
+func (c *fakeListClient) ListProjectsWithResponse(_ context.Context, _ uuid.UUID, params *client.ListProjectsParams, _ ...client.RequestEditorFn) (*client.ListProjectsResponse, error) {
+	c.pages++
+	page := paginate(c.projects, params.Page, params.Limit)
+	return &client.ListProjectsResponse{HTTPResponse: &http.Response{StatusCode: http.StatusOK}, JSON200: &page}, nil
+}
+
+func (c *fakeListClient) ListDomainsWithResponse(_ context.Context, _ uuid.UUID, params *client.ListDomainsParams, _ ...client.RequestEditorFn) (*client.ListDomainsResponse, error) {
+	c.pages++
+	page := paginate(c.domains, params.Page, params.Limit)
+	return &client.ListDomainsResponse{HTTPResponse: &http.Response{StatusCode: http.StatusOK}, JSON200: &page}, nil
+}
+
+func (c *fakeListClient) ListDeploymentsWithResponse(_ context.Context, projectID uuid.UUID, params *client.ListDeploymentsParams, _ ...client.RequestEditorFn) (*client.ListDeploymentsResponse, error) {
+	c.pages++
+	var deployments []client.Deployment
+	for _, d := range c.deployments {
+		if d.ProjectId == projectID {
+			deployments = append(deployments, d)
+		}
+	}
+	page := paginate(deployments, params.Page, params.Limit)
+	return &client.ListDeploymentsResponse{HTTPResponse: &http.Response{StatusCode: http.StatusOK}, JSON200: &page}, nil
+}
+
+// runList calls List on the list resource with a config holding the given
+// filter values, and collects the results and their diagnostics.
+func runList(t *testing.T, lr list.ListResource, r resource.ResourceWithIdentity, config map[string]any, includeResource bool, limit int64) ([]list.ListResult, diag.Diagnostics) {
+	t.Helper()
+	ctx := context.Background()
+
+	listSchemaResp := &list.ListResourceSchemaResponse{}
+	lr.ListResourceConfigSchema(ctx, list.ListResourceSchemaRequest{}, listSchemaResp)
+	objectType, ok := listSchemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	if !ok {
+		t.Fatal("list schema type is not an object")
+	}
+	attrs := map[string]tftypes.Value{}
+	for name, typ := range objectType.AttributeTypes {
+		attrs[name] = tftypes.NewValue(typ, config[name])
+	}
+
+	schemaResp := &resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, schemaResp)
+	identitySchemaResp := &resource.IdentitySchemaResponse{}
+	r.IdentitySchema(ctx, resource.IdentitySchemaRequest{}, identitySchemaResp)
+
+	req := list.ListRequest{
+		Config: tfsdk.Config{
+			Schema: listSchemaResp.Schema,
+			Raw:    tftypes.NewValue(objectType, attrs),
+		},
+		IncludeResource:        includeResource,
+		Limit:                  limit,
+		ResourceSchema:         schemaResp.Schema,
+		ResourceIdentitySchema: identitySchemaResp.IdentitySchema,
+	}
+	stream := &list.ListResultsStream{}
+	lr.List(ctx, req, stream)
+
+	var (
+		results []list.ListResult
+		diags   diag.Diagnostics
+	)
+	for result := range stream.Results {
+		diags.Append(result.Diagnostics...)
+		if result.Identity != nil {
+			results = append(results, result)
+		}
+	}
+	return results, diags
+}
+
+func TestProjectListResource(t *testing.T) {
+	organizationID := uuid.New()
+	fake := &fakeListClient{}
+	for i := range listPageSize + 2 {
+		name := "other"
+		if i%2 == 0 {
+			name = "app"
+		}
+		fake.projects = append(fake.projects, client.Project{
+			Id:        uuid.New(),
+			Name:      name,
+			CreatedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			UpdatedAt: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+		})
+	}
+	lr := &projectListResource{client: fake, organizationID: organizationID}
+
+	results, diags := runList(t, lr, &projectResource{}, map[string]any{"name_prefix": "ap"}, true, 0)
+	if diags.HasError() {
+		t.Fatalf("List() returned unexpected diagnostics: %v", diags)
+	}
+	if len(results) != listPageSize/2+1 {
+		t.Fatalf("List() returned %d results, want %d", len(results), listPageSize/2+1)
+	}
+	if fake.pages != 2 {
+		t.Errorf("List() requested %d pages, want 2", fake.pages)
+	}
+
+	var identity projectResourceIdentityModel
+	if diags := results[0].Identity.Get(context.Background(), &identity); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if identity.OrganizationID.ValueString() != organizationID.String() || identity.ID.ValueString() != fake.projects[0].Id.String() {
+		t.Errorf("identity = %v", identity)
+	}
+
+	var state projectResourceModel
+	if diags := results[0].Resource.Get(context.Background(), &state); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if state.Name.ValueString() != "app" || state.CreatedAt.ValueString() != "2025-01-01T00:00:00Z" {
+		t.Errorf("state = %v", state)
+	}
+	if results[0].DisplayName != "app" {
+		t.Errorf("display name = %s, want app", results[0].DisplayName)
+	}
+}
+
+func TestProjectListResource_Limit(t *testing.T) {
+	fake := &fakeListClient{}
+	for range listPageSize + 2 {
+		fake.projects = append(fake.projects, client.Project{Id: uuid.New(), Name: "app"})
+	}
+	lr := &projectListResource{client: fake, organizationID: uuid.New()}
+
+	results, diags := runList(t, lr, &projectResource{}, nil, false, 3)
+	if diags.HasError() {
+		t.Fatalf("List() returned unexpected diagnostics: %v", diags)
+	}
+	if len(results) != 3 {
+		t.Fatalf("List() returned %d results, want 3", len(results))
+	}
+	if fake.pages != 1 {
+		t.Errorf("List() requested %d pages, want 1", fake.pages)
+	}
+	if !results[0].Resource.Raw.IsNull() {
+		t.Error("resource should not be set when it is not requested")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
@@ -17,9 +18,11 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource                = &projectResource{}
-	_ resource.ResourceWithConfigure   = &projectResource{}
-	_ resource.ResourceWithImportState = &projectResource{}
+	_ resource.Resource                 = &projectResource{}
+	_ resource.ResourceWithConfigure    = &projectResource{}
+	_ resource.ResourceWithImportState  = &projectResource{}
+	_ resource.ResourceWithIdentity     = &projectResource{}
+	_ resource.ResourceWithUpgradeState = &projectResource{}
 )
 
 // NewProjectResource is a helper function to simplify the provider implementation.
@@ -27,6 +30,10 @@ func NewProjectResource() resource.Resource {
 	return &projectResource{}
 }
 
+// projectSchemaVersion is the version of the deno_project schema. See
+// deploymentSchemaVersion on bumping it.
+const projectSchemaVersion = 0
+
 // projectResource is the resource implementation.
 type projectResource struct {
 	client         client.ClientWithResponsesInterface
@@ -41,6 +48,19 @@ type projectResourceModel struct {
 	UpdatedAt types.String `tfsdk:"updated_at"`
 }
 
+// projectResourceIdentityModel maps the resource identity schema data.
+type projectResourceIdentityModel struct {
+	OrganizationID types.String `tfsdk:"organization_id"`
+	ID             types.String `tfsdk:"id"`
+}
+
+func newProjectResourceIdentity(organizationID uuid.UUID, projectID types.String) projectResourceIdentityModel {
+	return projectResourceIdentityModel{
+		OrganizationID: types.StringValue(organizationID.String()),
+		ID:             projectID,
+	}
+}
+
 // Metadata returns the resource type name.
 func (r *projectResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_project"
@@ -49,6 +69,7 @@ func (r *projectResource) Metadata(_ context.Context, req resource.MetadataReque
 // Schema defines the schema for the resource.
 func (r *projectResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version: projectSchemaVersion,
 		Description: `
 A resource for a Deno Deploy project.
 
@@ -83,8 +104,27 @@ A project consists of a collection of deployments and belongs to an organization
 	}
 }
 
+// IdentitySchema defines the identity of the resource.
+func (r *projectResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"organization_id": identityschema.StringAttribute{
+				OptionalForImport: true,
+				Description:       "The ID of the organization that the project belongs to. Defaults to the organization of the provider.",
+			},
+			"id": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The ID of the project.",
+			},
+		},
+	}
+}
+
 // Create creates the resource and sets the initial Terraform state.
 func (r *projectResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := startSpan(ctx, "deno_project.Create")
+	defer endSpan(span, &resp.Diagnostics)
+
 	// Retrieve values from plan
 	var plan projectResourceModel
 	diags := req.Plan.Get(ctx, &plan)
@@ -132,10 +172,16 @@ func (r *projectResource) Create(ctx context.Context, req resource.CreateRequest
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	diags = resp.Identity.Set(ctx, newProjectResourceIdentity(r.organizationID, plan.ID))
+	resp.Diagnostics.Append(diags...)
 }
 
 // Read refreshes the Terraform state with the latest data.
 func (r *projectResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := startSpan(ctx, "deno_project.Read")
+	defer endSpan(span, &resp.Diagnostics)
+
 	// Get current state
 	var state projectResourceModel
 	diags := req.State.Get(ctx, &state)
@@ -182,10 +228,16 @@ func (r *projectResource) Read(ctx context.Context, req resource.ReadRequest, re
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	diags = resp.Identity.Set(ctx, newProjectResourceIdentity(r.organizationID, state.ID))
+	resp.Diagnostics.Append(diags...)
 }
 
 // Update updates the resource and sets the updated Terraform state on success.
 func (r *projectResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := startSpan(ctx, "deno_project.Update")
+	defer endSpan(span, &resp.Diagnostics)
+
 	// Retrieve values from plan
 	var plan projectResourceModel
 	diags := req.Plan.Get(ctx, &plan)
@@ -231,10 +283,16 @@ func (r *projectResource) Update(ctx context.Context, req resource.UpdateRequest
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	diags = resp.Identity.Set(ctx, newProjectResourceIdentity(r.organizationID, plan.ID))
+	resp.Diagnostics.Append(diags...)
 }
 
 // Delete deletes the resource and removes the Terraform state on success.
 func (r *projectResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := startSpan(ctx, "deno_project.Delete")
+	defer endSpan(span, &resp.Diagnostics)
+
 	// Retrieve values from state
 	var state projectResourceModel
 	diags := req.State.Get(ctx, &state)
@@ -270,10 +328,22 @@ func (r *projectResource) Delete(ctx context.Context, req resource.DeleteRequest
 	}
 }
 
-// ImportState imports the existing resource into Terraform.
+// ImportState imports the existing resource into Terraform, either by the
+// project ID or by the identity.
 func (r *projectResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Retrieve import ID and save to id attribute
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	resp.Diagnostics.Append(checkImportOrganization(ctx, req, r.organizationID)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Retrieve import ID or identity and save to id attribute
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("id"), path.Root("id"), req, resp)
+}
+
+// UpgradeState returns the upgraders of the state written by the prior
+// versions of the schema, of which there are none yet.
+func (r *projectResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{}
 }
 
 // Configure adds the provider configured client to the resource.
@@ -293,6 +363,11 @@ func (r *projectResource) Configure(_ context.Context, req resource.ConfigureReq
 		return
 	}
 
+	resp.Diagnostics.Append(providerData.connect()...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	r.client = providerData.client
 	r.organizationID = providerData.organizationID
 }
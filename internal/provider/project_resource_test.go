@@ -3,12 +3,17 @@ package provider_test
 import (
 	"context"
 	"fmt"
+	"os"
 	"terraform-provider-deno/client"
 	"testing"
 
 	"github.com/google/uuid"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/querycheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
 	"github.com/thanhpk/randstr"
 )
 
@@ -45,6 +50,82 @@ func TestAccProject(t *testing.T) {
 	})
 }
 
+func TestAccProject_Import(t *testing.T) {
+	projName := randomProjectName()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccProjectDestroy(t),
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_12_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: genConfigWithProjectName(projName),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectIdentity("deno_project.test", map[string]knownvalue.Check{
+						"organization_id": knownvalue.StringExact(os.Getenv("DENO_DEPLOY_ORGANIZATION_ID")),
+						"id":              knownvalue.NotNull(),
+					}),
+				},
+			},
+			{
+				Config:            genConfigWithProjectName(projName),
+				ResourceName:      "deno_project.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config:          genConfigWithProjectName(projName),
+				ResourceName:    "deno_project.test",
+				ImportState:     true,
+				ImportStateKind: resource.ImportBlockWithResourceIdentity,
+			},
+		},
+	})
+}
+
+func TestAccProject_Query(t *testing.T) {
+	projName := randomProjectName()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccProjectDestroy(t),
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_14_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: genConfigWithProjectName(projName),
+				Check:  resource.ComposeTestCheckFunc(testAccProjectExists(t, "deno_project.test")),
+			},
+			{
+				Query: true,
+				Config: fmt.Sprintf(`
+					provider "deno" {}
+
+					list "deno_project" "test" {
+						provider = deno
+
+						config {
+							name_prefix = "%s"
+						}
+					}
+				`, projName),
+				QueryResultChecks: []querycheck.QueryResultCheck{
+					querycheck.ExpectLength("deno_project.test", 1),
+					querycheck.ExpectIdentity("deno_project.test", map[string]knownvalue.Check{
+						"organization_id": knownvalue.StringExact(os.Getenv("DENO_DEPLOY_ORGANIZATION_ID")),
+						"id":              knownvalue.NotNull(),
+					}),
+				},
+			},
+		},
+	})
+}
+
 func genConfigWithProjectName(projectName string) string {
 	return fmt.Sprintf(`
 		resource "deno_project" "test" {
@@ -3,13 +3,16 @@ package provider
 import (
 	"context"
 	"fmt"
-	"net/http"
+	"net/url"
 	"os"
+	"strconv"
+	"time"
 
-	"terraform-provider-deno/client"
-
-	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/action"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/list"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
@@ -20,7 +23,10 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ provider.Provider = &deployProvider{}
+	_ provider.Provider                  = &deployProvider{}
+	_ provider.ProviderWithFunctions     = &deployProvider{}
+	_ provider.ProviderWithListResources = &deployProvider{}
+	_ provider.ProviderWithActions       = &deployProvider{}
 )
 
 const (
@@ -44,13 +50,6 @@ type deployProvider struct {
 	version string
 }
 
-// deployProviderData is the provider-defined data that is intended to pass to
-// data sources and resoures as ProviderData.
-type deployProviderData struct {
-	client         client.ClientWithResponsesInterface
-	organizationID uuid.UUID
-}
-
 // Metadata returns the provider type name.
 func (p *deployProvider) Metadata(_ context.Context, _ provider.MetadataRequest, resp *provider.MetadataResponse) {
 	resp.TypeName = "deno"
@@ -67,16 +66,57 @@ The terraform provider for Deno Deploy offering management on Deno projects, cus
 			"token": schema.StringAttribute{
 				Optional:    true,
 				Sensitive:   true,
-				Description: "Access token. May be set by the DENO_DEPLOY_TOKEN environment variable. Tokens are created here: https://dash.deno.com/account#access-tokens.",
+				Description: "Access token. May be set by the DENO_DEPLOY_TOKEN environment variable. Tokens are created here: https://dash.deno.com/account#access-tokens. Only required once a resource, list resource or action is used.",
 			},
 			"organization_id": schema.StringAttribute{
 				Optional:    true,
-				Description: "Deploy organization id. May be set by the DENO_DEPLOY_ORGANIZATION_ID environment variable. The organization id is visible in the url of the organization's project list - https://dash.deno.com/orgs/<organization_id>",
+				Description: "Deploy organization id. May be set by the DENO_DEPLOY_ORGANIZATION_ID environment variable. The organization id is visible in the url of the organization's project list - https://dash.deno.com/orgs/<organization_id>. Only required once a resource, list resource or action is used.",
 			},
 			"host": schema.StringAttribute{
 				Optional:    true,
 				Description: "URI for the Deno API. For normal use cases this value doesn't need to be set, in which case it defaults to https://api.deno.com/v1. May be set by the DENO_API_HOST environment variable.",
 			},
+			"ca_cert_pem": schema.StringAttribute{
+				Optional:    true,
+				Description: "PEM-encoded certificates of the certificate authorities to trust in addition to the ones of the system, e.g. the one of a corporate proxy.",
+			},
+			"ca_cert_file": schema.StringAttribute{
+				Optional:    true,
+				Description: "The path to a file of PEM-encoded certificates of the certificate authorities to trust in addition to the ones of the system. It can be combined with `ca_cert_pem`.",
+			},
+			"client_cert": schema.StringAttribute{
+				Optional:    true,
+				Description: "PEM-encoded client certificate presented for mutual TLS, e.g. to an egress proxy. Must be set together with `client_key`. Use the `file` function to read it from a file.",
+			},
+			"client_key": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "PEM-encoded private key of `client_cert`.",
+			},
+			"proxy_url": schema.StringAttribute{
+				Optional:    true,
+				Description: "The URL of the proxy to send the API requests through, such as `http://proxy.example.com:3128`. Defaults to the proxy given by the HTTPS_PROXY and NO_PROXY environment variables.",
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether to skip verifying the TLS certificate of the API. This is only meant for testing against a local API, and must not be used otherwise. Defaults to `false`.",
+			},
+			"request_timeout": schema.StringAttribute{
+				Optional:    true,
+				Description: "The maximum time each API request may take, including uploading the assets of a deployment, such as `5m`. Defaults to no timeout.",
+			},
+			"max_requests_per_second": schema.Float64Attribute{
+				Optional:    true,
+				Description: fmt.Sprintf("The maximum rate of the API requests other than the uploads of deployments, shared by all the operations running in parallel. The rate is lowered temporarily when the API responds with 429 Too Many Requests. Defaults to `%d`.", defaultMaxRequestsPerSecond),
+			},
+			"max_concurrent_uploads": schema.Int64Attribute{
+				Optional:    true,
+				Description: fmt.Sprintf("The maximum number of deployments whose assets are uploaded at once. Defaults to `%d`.", defaultMaxConcurrentUploads),
+			},
+			"offline": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether to run without the Deno Deploy API. In offline mode, the data sources and functions that work locally, such as deno_assets, are available, while resources, list resources and actions report an error. This is useful for `terraform validate` and plans in CI jobs without credentials. May be set by the DENO_DEPLOY_OFFLINE environment variable. Defaults to `false`.",
+			},
 		},
 	}
 }
@@ -86,9 +126,22 @@ type deployProviderModel struct {
 	Host           types.String `tfsdk:"host"`
 	Token          types.String `tfsdk:"token"`
 	OrganizationID types.String `tfsdk:"organization_id"`
+	Offline        types.Bool   `tfsdk:"offline"`
+
+	CACertPEM          types.String `tfsdk:"ca_cert_pem"`
+	CACertFile         types.String `tfsdk:"ca_cert_file"`
+	ClientCert         types.String `tfsdk:"client_cert"`
+	ClientKey          types.String `tfsdk:"client_key"`
+	ProxyURL           types.String `tfsdk:"proxy_url"`
+	InsecureSkipVerify types.Bool   `tfsdk:"insecure_skip_verify"`
+	RequestTimeout     types.String `tfsdk:"request_timeout"`
+
+	MaxRequestsPerSecond types.Float64 `tfsdk:"max_requests_per_second"`
+	MaxConcurrentUploads types.Int64   `tfsdk:"max_concurrent_uploads"`
 }
 
-// Configure prepares a Deploy API client for data sources and resources.
+// Configure prepares the settings of the Deploy API client for data sources
+// and resources. The client itself is created lazily.
 func (p *deployProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
 	tflog.Info(ctx, "Configuring the Deno Deploy API client")
 
@@ -125,74 +178,166 @@ func (p *deployProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		host = DEFAULT_API_HOST
 	}
 
-	// If token or organization ID is empty, return an error
-	if token == "" {
-		resp.Diagnostics.AddError(
-			"Missing Deno Deploy API Token",
-			"The provider cannot create the Deno Deploy API client as there is a missing or empty value for the Deno Deploy API token. Set the value statically in the configuration, or use the DENO_DEPLOY_TOKEN environment variable.",
-		)
+	offline := false
+	if v := os.Getenv("DENO_DEPLOY_OFFLINE"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid DENO_DEPLOY_OFFLINE Environment Variable",
+				fmt.Sprintf("Could not parse %q as a boolean: %s", v, err.Error()),
+			)
+			return
+		}
+		offline = parsed
 	}
-	if rawOrganizationID == "" {
-		resp.Diagnostics.AddError(
-			"Missing Deno Deploy Organization ID",
-			"Organization ID needs to be given in order for the provider to interact with the Deno Deploy API. Set the value statically in the configuration, or use the DENO_DEPLOY_ORGANIZATION_ID environment variable.",
-		)
+	if !config.Offline.IsNull() {
+		offline = config.Offline.ValueBool()
 	}
 
+	httpConfig, diags := newHTTPClientConfig(config)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	organizationID, err := uuid.Parse(rawOrganizationID)
-
-	if err != nil {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("organization_id"),
-			"Invalid Deno Deploy Organization ID",
-			"Valid Organization ID needs to be given in order for the provider to interact with the Deno Deploy API.",
-		)
+	rateLimits, diags := newRateLimitConfig(config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
 	ctx = tflog.SetField(ctx, "deno_deploy_host", host)
 	ctx = tflog.SetField(ctx, "deno_deploy_token", token)
-	ctx = tflog.SetField(ctx, "deno_deploy_organization_id", organizationID)
+	ctx = tflog.SetField(ctx, "deno_deploy_organization_id", rawOrganizationID)
+	ctx = tflog.SetField(ctx, "deno_deploy_offline", offline)
 	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, "deno_deploy_token")
 
-	tflog.Debug(ctx, "Creating Deno Deploy API client")
-
-	// Create a new Deno Deploy client using the configuration values
-	addAuth := func(ctx context.Context, req *http.Request) error {
-		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
-		return nil
-	}
-	client, err := client.NewClientWithResponses(host, client.WithRequestEditorFn(addAuth))
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to Create HashiCups API Client",
-			"An unexpected error occurred when creating the HashiCups API client. "+
-				"If the error is not clear, please contact the provider developers.\n\n"+
-				"HashiCups Client Error: "+err.Error(),
-		)
-		return
-	}
-
+	// The credentials are checked when the API client is first needed, so
+	// that the data sources and functions work without them.
 	data := &deployProviderData{
-		client:         client,
-		organizationID: organizationID,
+		host:              host,
+		token:             token,
+		rawOrganizationID: rawOrganizationID,
+		offline:           offline,
+		httpConfig:        httpConfig,
+		rateLimits:        rateLimits,
+		userAgent:         userAgent(p.version, req.TerraformVersion),
 	}
 
 	// Make the Deno Deploy client available during DataSource and Resource
 	// type Configure methods.
 	resp.DataSourceData = data
 	resp.ResourceData = data
+	resp.ListResourceData = data
+	resp.ActionData = data
+
+	tflog.Info(ctx, "Configured Deno Deploy provider", map[string]any{"success": true})
+}
+
+// newHTTPClientConfig returns the configuration of the HTTP client from the
+// provider configuration.
+func newHTTPClientConfig(config deployProviderModel) (httpClientConfig, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	httpConfig := httpClientConfig{
+		CACertPEM:          config.CACertPEM.ValueString(),
+		CACertFile:         config.CACertFile.ValueString(),
+		ClientCert:         config.ClientCert.ValueString(),
+		ClientKey:          config.ClientKey.ValueString(),
+		InsecureSkipVerify: config.InsecureSkipVerify.ValueBool(),
+	}
+
+	if (httpConfig.ClientCert == "") != (httpConfig.ClientKey == "") {
+		diags.AddAttributeError(
+			path.Root("client_cert"),
+			"Incomplete Client Certificate",
+			"Both client_cert and client_key must be set for mutual TLS.",
+		)
+	}
 
-	tflog.Info(ctx, "Configured Deno Deploy client", map[string]any{"success": true})
+	if v := config.ProxyURL.ValueString(); v != "" {
+		proxyURL, err := url.Parse(v)
+		if err != nil || proxyURL.Scheme == "" || proxyURL.Host == "" {
+			diags.AddAttributeError(
+				path.Root("proxy_url"),
+				"Invalid Proxy URL",
+				fmt.Sprintf("Could not parse %q as an absolute URL.", v),
+			)
+		}
+		httpConfig.ProxyURL = proxyURL
+	}
+
+	if v := config.RequestTimeout.ValueString(); v != "" {
+		timeout, err := time.ParseDuration(v)
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("request_timeout"),
+				"Invalid Request Timeout",
+				fmt.Sprintf("Could not parse %q as a duration: %s", v, err.Error()),
+			)
+		} else if timeout <= 0 {
+			diags.AddAttributeError(
+				path.Root("request_timeout"),
+				"Invalid Request Timeout",
+				fmt.Sprintf("The timeout must be positive, got %s", v),
+			)
+		}
+		httpConfig.RequestTimeout = timeout
+	}
+
+	if httpConfig.InsecureSkipVerify {
+		diags.AddAttributeWarning(
+			path.Root("insecure_skip_verify"),
+			"TLS Certificate Verification Disabled",
+			"The TLS certificate of the Deno Deploy API is not verified, so the token may be sent to anyone. Only use insecure_skip_verify for testing.",
+		)
+	}
+
+	return httpConfig, diags
+}
+
+// newRateLimitConfig returns the rate limit of the API requests from the
+// provider configuration.
+func newRateLimitConfig(config deployProviderModel) (rateLimitConfig, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	rateLimits := rateLimitConfig{
+		MaxRequestsPerSecond: defaultMaxRequestsPerSecond,
+		MaxConcurrentUploads: defaultMaxConcurrentUploads,
+	}
+
+	if !config.MaxRequestsPerSecond.IsNull() {
+		v := config.MaxRequestsPerSecond.ValueFloat64()
+		if v <= 0 {
+			diags.AddAttributeError(
+				path.Root("max_requests_per_second"),
+				"Invalid Maximum Requests per Second",
+				fmt.Sprintf("The rate must be positive, got %g", v),
+			)
+		}
+		rateLimits.MaxRequestsPerSecond = v
+	}
+
+	if !config.MaxConcurrentUploads.IsNull() {
+		v := config.MaxConcurrentUploads.ValueInt64()
+		if v <= 0 {
+			diags.AddAttributeError(
+				path.Root("max_concurrent_uploads"),
+				"Invalid Maximum Concurrent Uploads",
+				fmt.Sprintf("The number of uploads must be positive, got %d", v),
+			)
+		}
+		rateLimits.MaxConcurrentUploads = int(v)
+	}
+
+	return rateLimits, diags
 }
 
 // DataSources defines the data sources implemented in the provider.
 func (p *deployProvider) DataSources(_ context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewAssetsResource,
+		NewDotenvDataSource,
 	}
 }
 
@@ -206,3 +351,31 @@ func (p *deployProvider) Resources(_ context.Context) []func() resource.Resource
 		NewDeploymentResource,
 	}
 }
+
+// ListResources defines the list resources implemented in the provider.
+func (p *deployProvider) ListResources(_ context.Context) []func() list.ListResource {
+	return []func() list.ListResource{
+		NewProjectListResource,
+		NewDomainListResource,
+		NewDeploymentListResource,
+	}
+}
+
+// Actions defines the actions implemented in the provider.
+func (p *deployProvider) Actions(_ context.Context) []func() action.Action {
+	return []func() action.Action{
+		NewVerifyDomainAction,
+		NewProvisionCertificateAction,
+		NewPointDomainAction,
+	}
+}
+
+// Functions defines the provider-defined functions implemented in the provider.
+func (p *deployProvider) Functions(_ context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewGitSha1Function,
+		NewGitSha1FileFunction,
+		NewEncodePathFunction,
+		NewInlineAssetFunction,
+	}
+}
@@ -0,0 +1,135 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"terraform-provider-deno/client"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// deployProviderData is the provider-defined data that is intended to pass to
+// data sources and resoures as ProviderData.
+//
+// The API client is created on the first call of connect rather than when the
+// provider is configured, so that configurations using only the local data
+// sources and functions work without credentials.
+type deployProviderData struct {
+	host              string
+	token             string
+	rawOrganizationID string
+	offline           bool
+	httpConfig        httpClientConfig
+	rateLimits        rateLimitConfig
+	userAgent         string
+
+	once           sync.Once
+	diags          diag.Diagnostics
+	client         client.ClientWithResponsesInterface
+	organizationID uuid.UUID
+	// reads coalesces and caches the GET requests of the client during the
+	// operation.
+	reads *readCache
+}
+
+// connect creates the Deno Deploy API client if it's not created yet, and
+// returns the errors if the provider is in offline mode or the credentials
+// are missing or invalid. The errors are returned from every call.
+func (d *deployProviderData) connect() diag.Diagnostics {
+	d.once.Do(func() {
+		d.diags = d.createClient()
+	})
+	return d.diags
+}
+
+func (d *deployProviderData) createClient() diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if d.offline {
+		diags.AddError(
+			"Deno Deploy API Unavailable in Offline Mode",
+			"The provider is configured with offline = true, so resources, list resources and actions that call the Deno Deploy API can't be used. Only the data sources and functions that work locally are available. Unset offline, or the DENO_DEPLOY_OFFLINE environment variable, to use them.",
+		)
+		return diags
+	}
+
+	// If token or organization ID is empty, return an error
+	if d.token == "" {
+		diags.AddError(
+			"Missing Deno Deploy API Token",
+			"The provider cannot create the Deno Deploy API client as there is a missing or empty value for the Deno Deploy API token. Set the value statically in the configuration, or use the DENO_DEPLOY_TOKEN environment variable.",
+		)
+	}
+	if d.rawOrganizationID == "" {
+		diags.AddError(
+			"Missing Deno Deploy Organization ID",
+			"Organization ID needs to be given in order for the provider to interact with the Deno Deploy API. Set the value statically in the configuration, or use the DENO_DEPLOY_ORGANIZATION_ID environment variable.",
+		)
+	}
+	if diags.HasError() {
+		return diags
+	}
+
+	organizationID, err := uuid.Parse(d.rawOrganizationID)
+	if err != nil {
+		diags.AddError(
+			"Invalid Deno Deploy Organization ID",
+			"Valid Organization ID needs to be given in order for the provider to interact with the Deno Deploy API.",
+		)
+		return diags
+	}
+
+	httpClient, err := d.httpConfig.newHTTPClient()
+	if err != nil {
+		diags.AddError(
+			"Unable to Create Deno Deploy API Client",
+			fmt.Sprintf("Could not configure the HTTP client: %s", err.Error()),
+		)
+		return diags
+	}
+
+	hostURL, err := url.Parse(d.host)
+	if err != nil {
+		diags.AddError(
+			"Unable to Create Deno Deploy API Client",
+			fmt.Sprintf("Could not parse the host %s: %s", d.host, err.Error()),
+		)
+		return diags
+	}
+	limited := newRateLimitDoer(newLoggingDoer(httpClient), d.rateLimits, hostURL.Path)
+	d.reads = newReadCache(limited, hostURL.Path)
+
+	// Create a new Deno Deploy client using the configuration values
+	addAuth := func(ctx context.Context, req *http.Request) error {
+		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", d.token))
+		return nil
+	}
+	addUserAgent := func(ctx context.Context, req *http.Request) error {
+		req.Header.Set("User-Agent", d.userAgent)
+		return nil
+	}
+	c, err := client.NewClientWithResponses(
+		d.host,
+		client.WithHTTPClient(&tracingDoer{doer: d.reads}),
+		client.WithRequestEditorFn(addAuth),
+		client.WithRequestEditorFn(addUserAgent),
+	)
+	if err != nil {
+		diags.AddError(
+			"Unable to Create Deno Deploy API Client",
+			"An unexpected error occurred when creating the Deno Deploy API client. "+
+				"If the error is not clear, please contact the provider developers.\n\n"+
+				"Deno Deploy Client Error: "+err.Error(),
+		)
+		return diags
+	}
+
+	d.client = c
+	d.organizationID = organizationID
+	return diags
+}
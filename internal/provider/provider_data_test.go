@@ -0,0 +1,164 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestDeployProviderDataConnect(t *testing.T) {
+	organizationID := uuid.New()
+
+	tests := []struct {
+		name            string
+		data            *deployProviderData
+		expectedSummary []string
+	}{
+		{
+			name: "valid",
+			data: &deployProviderData{host: DEFAULT_API_HOST, token: "token", rawOrganizationID: organizationID.String()},
+		},
+		{
+			name:            "missing credentials",
+			data:            &deployProviderData{host: DEFAULT_API_HOST},
+			expectedSummary: []string{"Missing Deno Deploy API Token", "Missing Deno Deploy Organization ID"},
+		},
+		{
+			name:            "invalid organization ID",
+			data:            &deployProviderData{host: DEFAULT_API_HOST, token: "token", rawOrganizationID: "my-org"},
+			expectedSummary: []string{"Invalid Deno Deploy Organization ID"},
+		},
+		{
+			name:            "offline",
+			data:            &deployProviderData{host: DEFAULT_API_HOST, token: "token", rawOrganizationID: organizationID.String(), offline: true},
+			expectedSummary: []string{"Deno Deploy API Unavailable in Offline Mode"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The result is the same on every call
+			for range 2 {
+				diags := tt.data.connect()
+				if len(diags) != len(tt.expectedSummary) {
+					t.Fatalf("connect() = %v, want %v", diags, tt.expectedSummary)
+				}
+				for i, d := range diags {
+					if d.Summary() != tt.expectedSummary[i] {
+						t.Errorf("diagnostic %d = %q, want %q", i, d.Summary(), tt.expectedSummary[i])
+					}
+				}
+			}
+
+			if tt.expectedSummary != nil {
+				if tt.data.client != nil {
+					t.Error("client should not be created")
+				}
+				return
+			}
+			if tt.data.client == nil || tt.data.organizationID != organizationID {
+				t.Errorf("client = %v, organizationID = %s", tt.data.client, tt.data.organizationID)
+			}
+		})
+	}
+}
+
+// configureProvider configures the provider with the given attribute values,
+// leaving the others null, and returns the provider data.
+func configureProvider(t *testing.T, config map[string]any) (*deployProviderData, provider.ConfigureResponse) {
+	t.Helper()
+	ctx := context.Background()
+
+	p := &deployProvider{version: "test"}
+	schemaResp := &provider.SchemaResponse{}
+	p.Schema(ctx, provider.SchemaRequest{}, schemaResp)
+	objectType, ok := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	if !ok {
+		t.Fatal("provider schema type is not an object")
+	}
+	attrs := map[string]tftypes.Value{}
+	for name, typ := range objectType.AttributeTypes {
+		attrs[name] = tftypes.NewValue(typ, config[name])
+	}
+
+	resp := provider.ConfigureResponse{}
+	p.Configure(ctx, provider.ConfigureRequest{
+		Config: tfsdk.Config{
+			Schema: schemaResp.Schema,
+			Raw:    tftypes.NewValue(objectType, attrs),
+		},
+	}, &resp)
+
+	data, _ := resp.ResourceData.(*deployProviderData)
+	return data, resp
+}
+
+func TestProviderConfigure_WithoutCredentials(t *testing.T) {
+	t.Setenv("DENO_DEPLOY_TOKEN", "")
+	t.Setenv("DENO_DEPLOY_ORGANIZATION_ID", "")
+	t.Setenv("DENO_DEPLOY_OFFLINE", "")
+
+	data, resp := configureProvider(t, nil)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Configure() returned unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	if data == nil || resp.DataSourceData != data || resp.ActionData != data {
+		t.Fatalf("provider data is not passed: %+v", resp)
+	}
+
+	// The credentials are checked once a resource needs the API
+	configureResp := &resource.ConfigureResponse{}
+	NewProjectResource().(resource.ResourceWithConfigure).Configure(context.Background(), resource.ConfigureRequest{ProviderData: data}, configureResp)
+	if !configureResp.Diagnostics.HasError() || configureResp.Diagnostics[0].Summary() != "Missing Deno Deploy API Token" {
+		t.Errorf("resource Configure() = %v, want the missing token error", configureResp.Diagnostics)
+	}
+}
+
+func TestProviderConfigure_Offline(t *testing.T) {
+	t.Setenv("DENO_DEPLOY_TOKEN", "token")
+	t.Setenv("DENO_DEPLOY_ORGANIZATION_ID", uuid.NewString())
+
+	t.Run("attribute", func(t *testing.T) {
+		t.Setenv("DENO_DEPLOY_OFFLINE", "")
+
+		data, resp := configureProvider(t, map[string]any{"offline": true})
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("Configure() returned unexpected diagnostics: %v", resp.Diagnostics)
+		}
+		if !data.offline {
+			t.Error("offline = false, want true")
+		}
+	})
+
+	t.Run("environment variable", func(t *testing.T) {
+		t.Setenv("DENO_DEPLOY_OFFLINE", "1")
+
+		data, resp := configureProvider(t, nil)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("Configure() returned unexpected diagnostics: %v", resp.Diagnostics)
+		}
+		if !data.offline {
+			t.Error("offline = false, want true")
+		}
+
+		// The attribute takes precedence over the environment variable
+		data, _ = configureProvider(t, map[string]any{"offline": false})
+		if data.offline {
+			t.Error("offline = true, want false")
+		}
+	})
+
+	t.Run("invalid environment variable", func(t *testing.T) {
+		t.Setenv("DENO_DEPLOY_OFFLINE", "maybe")
+
+		_, resp := configureProvider(t, nil)
+		if !resp.Diagnostics.HasError() {
+			t.Error("Configure() should fail")
+		}
+	})
+}
@@ -8,12 +8,14 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"regexp"
 	"terraform-provider-deno/client"
 	"terraform-provider-deno/internal/provider"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 )
 
 // testAccProtoV6ProviderFactories are used to instantiate a provider during
@@ -54,3 +56,73 @@ func ensureEnvVarExist(t *testing.T, name string) {
 		t.Fatalf("missing environment variable: %s", name)
 	}
 }
+
+func TestAccProvider_Offline(t *testing.T) {
+	// No credentials are needed in offline mode
+	t.Setenv("DENO_DEPLOY_TOKEN", "")
+	t.Setenv("DENO_DEPLOY_ORGANIZATION_ID", "")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					provider "deno" {
+						offline = true
+					}
+
+					data "deno_assets" "test" {
+						path = "./testdata/single-file"
+						pattern = "*.ts"
+					}
+
+					output "sha1" {
+						value = provider::deno::git_sha1("hello")
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.deno_assets.test", "output.%", "1"),
+					resource.TestCheckOutput("sha1", "b6fc4c620b67d95f953a5c1c1230aaab5db5a1b0"),
+				),
+			},
+			{
+				Config: `
+					provider "deno" {
+						offline = true
+					}
+
+					resource "deno_project" "test" {}
+				`,
+				ExpectError: regexp.MustCompile("Deno Deploy API Unavailable in Offline Mode"),
+			},
+		},
+	})
+}
+
+func TestAccProvider_LazyCredentials(t *testing.T) {
+	t.Setenv("DENO_DEPLOY_TOKEN", "")
+	t.Setenv("DENO_DEPLOY_ORGANIZATION_ID", "")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// The data sources work without credentials
+				Config: `
+					data "deno_assets" "test" {
+						path = "./testdata/single-file"
+						pattern = "*.ts"
+					}
+				`,
+				Check: resource.TestCheckResourceAttr("data.deno_assets.test", "output.%", "1"),
+			},
+			{
+				// The credentials are checked once a resource is used
+				Config: `
+					resource "deno_project" "test" {}
+				`,
+				ExpectError: regexp.MustCompile("Missing Deno Deploy API Token"),
+			},
+		},
+	})
+}
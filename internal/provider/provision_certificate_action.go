@@ -0,0 +1,166 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-deno/client"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ action.Action                   = &provisionCertificateAction{}
+	_ action.ActionWithConfigure      = &provisionCertificateAction{}
+	_ action.ActionWithValidateConfig = &provisionCertificateAction{}
+)
+
+// NewProvisionCertificateAction is a helper function to simplify the provider implementation.
+func NewProvisionCertificateAction() action.Action {
+	return &provisionCertificateAction{}
+}
+
+// provisionCertificateAction is the action implementation.
+type provisionCertificateAction struct {
+	client         client.ClientWithResponsesInterface
+	organizationID uuid.UUID
+
+	// poller overrides the polling of the provisioning status in tests.
+	poller *poller
+}
+
+// provisionCertificateActionModel maps the action schema data.
+type provisionCertificateActionModel struct {
+	DomainID types.String `tfsdk:"domain_id"`
+	Timeout  types.String `tfsdk:"timeout"`
+}
+
+// Metadata returns the action type name.
+func (a *provisionCertificateAction) Metadata(_ context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_provision_certificate"
+}
+
+// Schema defines the schema for the action.
+func (a *provisionCertificateAction) Schema(_ context.Context, _ action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: `
+Provisions TLS certificates for a verified custom domain, waiting until the provisioning finishes.
+
+This does the same as deno_domain_certificate resource without keeping anything in the Terraform state, so it can be run again with ` + "`terraform apply -invoke=action.deno_provision_certificate.<name>`" + ` e.g. to retry a failed provisioning. The provisioning status is reported while waiting.
+		`,
+		Attributes: map[string]schema.Attribute{
+			"domain_id": schema.StringAttribute{
+				Required:    true,
+				Description: "The ID of the domain to provision certificates for.",
+			},
+			"timeout": schema.StringAttribute{
+				Optional:    true,
+				Description: "The maximum time to wait for the provisioning, such as `30m`. Defaults to `10m`.",
+			},
+		},
+	}
+}
+
+// ValidateConfig validates the timeout at plan time.
+func (a *provisionCertificateAction) ValidateConfig(ctx context.Context, req action.ValidateConfigRequest, resp *action.ValidateConfigResponse) {
+	var config provisionCertificateActionModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, diags = parseActionTimeout(config.Timeout, defaultCertificateProvisioningTimeout)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Invoke provisions the certificates, streaming the progress to Terraform.
+func (a *provisionCertificateAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var config provisionCertificateActionModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := parseActionTimeout(config.Timeout, defaultCertificateProvisioningTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	domainID, err := uuid.Parse(config.DomainID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Unable to Provision Certificates for Domain %s", config.DomainID),
+			fmt.Sprintf("Could not parse domain ID %s: %s", config.DomainID, err.Error()),
+		)
+		return
+	}
+
+	ctx = withProgress(ctx, resp.SendProgress)
+
+	// Call the API to trigger provisioning
+	reportProgress(ctx, "Provisioning certificates for domain %s", domainID)
+	result, err := a.client.ProvisionDomainCertificatesWithResponse(ctx, domainID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Unable to Provision Certificates for Domain %s", domainID),
+			fmt.Sprintf("API returned error: %s", err.Error()),
+		)
+		return
+	}
+	if client.RespIsError(result) {
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Unable to Provision Certificates for Domain %s", domainID),
+			client.APIErrorDetail(result.HTTPResponse, result.Body),
+		)
+		return
+	}
+
+	// Wait for the provisioning to finish
+	provisioning := &certificateProvisioningResource{client: a.client, organizationID: a.organizationID, poller: a.poller}
+	status, diags := provisioning.waitForProvisioning(ctx, domainID, timeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if detail := status.failureDetail(); detail != "" {
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Unable to Provision Certificates for Domain %s", domainID),
+			detail,
+		)
+		return
+	}
+	reportProgress(ctx, "Certificates for domain %s are provisioned", domainID)
+}
+
+// Configure adds the provider configured client to the action.
+func (a *provisionCertificateAction) Configure(_ context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*deployProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Action Configure Type",
+			fmt.Sprintf("Expected *deployProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	resp.Diagnostics.Append(providerData.connect()...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	a.client = providerData.client
+	a.organizationID = providerData.organizationID
+}
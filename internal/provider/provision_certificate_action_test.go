@@ -0,0 +1,75 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"terraform-provider-deno/client"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// fakeProvisionClient records the provisioning requests and serves the
+// provisioning statuses like fakeProvisioningClient.
+type fakeProvisionClient struct {
+	fakeProvisioningClient
+
+	provisioned []uuid.UUID
+}
+
+func (c *fakeProvisionClient) ProvisionDomainCertificatesWithResponse(_ context.Context, domainID uuid.UUID, _ ...client.RequestEditorFn) (*client.ProvisionDomainCertificatesResponse, error) {
+	c.provisioned = append(c.provisioned, domainID)
+	return &client.ProvisionDomainCertificatesResponse{HTTPResponse: &http.Response{StatusCode: http.StatusOK}}, nil
+}
+
+func TestProvisionCertificateAction(t *testing.T) {
+	tests := []struct {
+		name          string
+		statuses      []string
+		expectedError string
+	}{
+		{
+			name:     "success",
+			statuses: []string{"pending", "success"},
+		},
+		{
+			name:          "failed",
+			statuses:      []string{"pending", "failed"},
+			expectedError: "Provisioning failed with code failed: rate limited by the CA",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeProvisionClient{}
+			for _, s := range tt.statuses {
+				fake.statuses = append(fake.statuses, provisioningStatus(t, s, "rate limited by the CA"))
+			}
+			a := &provisionCertificateAction{
+				client: fake,
+				poller: &poller{Interval: time.Second, clock: &fakeClock{}},
+			}
+			domainID := uuid.New()
+
+			messages, diags := invokeAction(t, a, map[string]any{"domain_id": domainID.String()})
+			if len(fake.provisioned) != 1 || fake.provisioned[0] != domainID {
+				t.Errorf("provisioned = %v, want %s", fake.provisioned, domainID)
+			}
+			if !strings.Contains(strings.Join(messages, "\n"), "is pending (attempt 1)") {
+				t.Errorf("messages = %q, want the pending status", messages)
+			}
+
+			if tt.expectedError == "" {
+				if diags.HasError() {
+					t.Fatalf("Invoke() returned unexpected diagnostics: %v", diags)
+				}
+				return
+			}
+			if !diags.HasError() || !strings.HasPrefix(diags[0].Detail(), tt.expectedError) {
+				t.Errorf("diagnostics = %v, want %q", diags, tt.expectedError)
+			}
+		})
+	}
+}
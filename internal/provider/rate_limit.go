@@ -0,0 +1,220 @@
+package provider
+
+import (
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"terraform-provider-deno/client"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/time/rate"
+)
+
+const (
+	defaultMaxRequestsPerSecond = 10
+	defaultMaxConcurrentUploads = 2
+
+	// minRequestsPerSecond is the lowest rate that 429 responses lower the
+	// rate to.
+	minRequestsPerSecond = 0.5
+	// rateRecoveryThreshold is the number of consecutive responses other than
+	// 429 after which a lowered rate is raised again.
+	rateRecoveryThreshold = 10
+	// maxRateLimitRetries is the number of times a request rejected with 429
+	// is sent again.
+	maxRateLimitRetries = 3
+	// defaultRateLimitPause is how long the requests are paused after a 429
+	// response without Retry-After.
+	defaultRateLimitPause = time.Second
+	// maxRateLimitPause caps the pause requested by Retry-After.
+	maxRateLimitPause = time.Minute
+)
+
+// rateLimitConfig is the client-side rate limit of the requests to the Deno
+// Deploy API, shared by all the operations of the provider.
+type rateLimitConfig struct {
+	// MaxRequestsPerSecond is the rate of the requests other than uploads.
+	MaxRequestsPerSecond float64
+	// MaxConcurrentUploads is the number of deployments uploaded at once.
+	MaxConcurrentUploads int
+}
+
+// rateLimitDoer is an HTTP request doer that limits the requests to the Deno
+// Deploy API. Uploads of deployments are budgeted separately from the other
+// requests, so that a few large uploads don't hold up the cheap ones: at most
+// MaxConcurrentUploads of them are in flight, while the other requests take a
+// token from a bucket refilled at MaxRequestsPerSecond.
+//
+// When a request is rejected with 429, the rate is halved, all the requests
+// are paused for the time given by Retry-After, and the request is sent
+// again. The rate is raised back step by step as the requests succeed.
+type rateLimitDoer struct {
+	doer     client.HttpRequestDoer
+	basePath string
+	maxRate  rate.Limit
+	limiter  *rate.Limiter
+	uploads  chan struct{}
+	// pause is how long the requests are paused after a 429 response without
+	// Retry-After.
+	pause time.Duration
+
+	mu          sync.Mutex
+	pausedUntil time.Time
+	successes   int
+}
+
+// newRateLimitDoer returns a doer sending the requests with the doer to the
+// API at basePath within the limits of the config. The limits that are not
+// set take the default values.
+func newRateLimitDoer(doer client.HttpRequestDoer, config rateLimitConfig, basePath string) *rateLimitDoer {
+	if config.MaxRequestsPerSecond <= 0 {
+		config.MaxRequestsPerSecond = defaultMaxRequestsPerSecond
+	}
+	if config.MaxConcurrentUploads <= 0 {
+		config.MaxConcurrentUploads = defaultMaxConcurrentUploads
+	}
+
+	maxRate := rate.Limit(config.MaxRequestsPerSecond)
+	return &rateLimitDoer{
+		doer:     doer,
+		basePath: strings.TrimSuffix(basePath, "/"),
+		maxRate:  maxRate,
+		limiter:  rate.NewLimiter(maxRate, int(math.Ceil(config.MaxRequestsPerSecond))),
+		uploads:  make(chan struct{}, config.MaxConcurrentUploads),
+		pause:    defaultRateLimitPause,
+	}
+}
+
+// Do sends the request once it's within the limits, and sends it again if it
+// is rejected with 429.
+func (d *rateLimitDoer) Do(req *http.Request) (*http.Response, error) {
+	upload := d.isUpload(req)
+
+	for attempt := 0; ; attempt++ {
+		release, err := d.acquire(req, upload)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := d.doer.Do(req)
+		release()
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode != http.StatusTooManyRequests {
+			d.succeeded()
+			return resp, nil
+		}
+
+		d.throttle(req, retryAfter(resp.Header.Get("Retry-After"), d.pause))
+
+		// The request can't be sent again if its body was consumed
+		if attempt == maxRateLimitRetries || (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+			return resp, nil
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// isUpload reports whether the request creates a deployment, uploading its
+// assets.
+func (d *rateLimitDoer) isUpload(req *http.Request) bool {
+	p := strings.TrimPrefix(req.URL.Path, d.basePath)
+	return req.Method == http.MethodPost && strings.HasPrefix(p, "/projects/") && strings.HasSuffix(p, "/deployments")
+}
+
+// acquire waits for the end of the pause and for an upload slot or a token of
+// the bucket. The returned function releases the upload slot.
+func (d *rateLimitDoer) acquire(req *http.Request, upload bool) (func(), error) {
+	ctx := req.Context()
+
+	d.mu.Lock()
+	delay := time.Until(d.pausedUntil)
+	d.mu.Unlock()
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	if !upload {
+		if err := d.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+		return func() {}, nil
+	}
+
+	select {
+	case d.uploads <- struct{}{}:
+		return func() { <-d.uploads }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// throttle halves the rate and pauses the requests after a 429 response.
+func (d *rateLimitDoer) throttle(req *http.Request, pause time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.successes = 0
+	if until := time.Now().Add(pause); until.After(d.pausedUntil) {
+		d.pausedUntil = until
+	}
+	limit := max(d.limiter.Limit()/2, minRequestsPerSecond)
+	d.limiter.SetLimit(limit)
+
+	tflog.SubsystemWarn(apiLogContext(req), apiLogSubsystem, "API rate limit exceeded, lowering the request rate", map[string]any{
+		"requests_per_second": float64(limit),
+		"pause":               pause.String(),
+	})
+}
+
+// succeeded raises a lowered rate after rateRecoveryThreshold consecutive
+// responses other than 429.
+func (d *rateLimitDoer) succeeded() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.limiter.Limit() >= d.maxRate {
+		return
+	}
+	d.successes++
+	if d.successes < rateRecoveryThreshold {
+		return
+	}
+	d.successes = 0
+	d.limiter.SetLimit(min(d.limiter.Limit()*2, d.maxRate))
+}
+
+// retryAfter returns the pause requested by the Retry-After header, which is
+// either a number of seconds or a date, or defaultPause if it's missing or
+// invalid. It's capped at maxRateLimitPause.
+func retryAfter(header string, defaultPause time.Duration) time.Duration {
+	pause := defaultPause
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		pause = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(header); err == nil {
+		pause = max(time.Until(date), 0)
+	}
+	return min(pause, maxRateLimitPause)
+}
@@ -0,0 +1,211 @@
+package provider
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"golang.org/x/time/rate"
+)
+
+// doerFunc adapts a function to an HTTP request doer.
+type doerFunc func(req *http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// okResponse returns an empty response with the status.
+func okResponse(status int) *http.Response {
+	return &http.Response{StatusCode: status, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(""))}
+}
+
+// sendRequest sends the request through the doer and fails the test on error.
+func sendRequest(t *testing.T, d *rateLimitDoer, method, path, body string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(method, "https://api.deno.com/v1"+path, strings.NewReader(body))
+	if err != nil {
+		t.Error(err)
+		return nil
+	}
+	resp, err := d.Do(req)
+	if err != nil {
+		t.Errorf("Do() returned unexpected error: %s", err)
+		return nil
+	}
+	resp.Body.Close()
+	return resp
+}
+
+func TestRateLimitDoer_Rate(t *testing.T) {
+	d := newRateLimitDoer(doerFunc(func(*http.Request) (*http.Response, error) {
+		return okResponse(http.StatusOK), nil
+	}), rateLimitConfig{MaxRequestsPerSecond: 100}, "/v1")
+
+	// The burst of 100 requests is sent at once, and the next 10 take 100ms
+	start := time.Now()
+	for range 110 {
+		sendRequest(t, d, http.MethodGet, "/projects/p", "")
+	}
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("110 requests took %s, want at least 100ms", elapsed)
+	}
+}
+
+func TestRateLimitDoer_Uploads(t *testing.T) {
+	release := make(chan struct{})
+	var inFlight, maxInFlight atomic.Int32
+	d := newRateLimitDoer(doerFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Method == http.MethodPost {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				m := maxInFlight.Load()
+				if n <= m || maxInFlight.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			<-release
+		}
+		return okResponse(http.StatusOK), nil
+	}), rateLimitConfig{MaxRequestsPerSecond: 1, MaxConcurrentUploads: 2}, "/v1")
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sendRequest(t, d, http.MethodPost, "/projects/p/deployments", `{"assets":{}}`)
+		}()
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for inFlight.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	// GETs are not held up by the uploads in flight
+	done := make(chan struct{})
+	go func() {
+		sendRequest(t, d, http.MethodGet, "/projects/p", "")
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("GET was blocked by the uploads")
+	}
+
+	close(release)
+	wg.Wait()
+
+	if got := maxInFlight.Load(); got != 2 {
+		t.Errorf("uploads in flight = %d, want 2", got)
+	}
+}
+
+func TestRateLimitDoer_TooManyRequests(t *testing.T) {
+	var bodies []string
+	d := newRateLimitDoer(doerFunc(func(req *http.Request) (*http.Response, error) {
+		b, _ := io.ReadAll(req.Body)
+		bodies = append(bodies, string(b))
+		if len(bodies) == 1 {
+			resp := okResponse(http.StatusTooManyRequests)
+			resp.Header.Set("Retry-After", "0")
+			return resp, nil
+		}
+		return okResponse(http.StatusCreated), nil
+	}), rateLimitConfig{MaxRequestsPerSecond: 8}, "/v1")
+
+	resp := sendRequest(t, d, http.MethodPost, "/organizations/o/projects", `{"name":"my-project"}`)
+	if resp == nil || resp.StatusCode != http.StatusCreated {
+		t.Fatalf("response = %v, want 201 after the retry", resp)
+	}
+	if len(bodies) != 2 || bodies[1] != `{"name":"my-project"}` {
+		t.Errorf("bodies = %q, want the body sent twice", bodies)
+	}
+	if got := d.limiter.Limit(); got != 4 {
+		t.Errorf("rate = %v, want 4 after 429", got)
+	}
+
+	// The rate is raised back after enough successful requests
+	for range rateRecoveryThreshold {
+		sendRequest(t, d, http.MethodGet, "/projects/p", "")
+	}
+	if got := d.limiter.Limit(); got != 8 {
+		t.Errorf("rate = %v, want 8 after recovery", got)
+	}
+}
+
+func TestRateLimitDoer_TooManyRequestsExhausted(t *testing.T) {
+	var attempts int
+	d := newRateLimitDoer(doerFunc(func(*http.Request) (*http.Response, error) {
+		attempts++
+		return okResponse(http.StatusTooManyRequests), nil
+	}), rateLimitConfig{MaxRequestsPerSecond: 1000}, "/v1")
+	d.pause = time.Millisecond
+
+	resp := sendRequest(t, d, http.MethodGet, "/projects/p", "")
+	if resp == nil || resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("response = %v, want 429", resp)
+	}
+	if attempts != maxRateLimitRetries+1 {
+		t.Errorf("attempts = %d, want %d", attempts, maxRateLimitRetries+1)
+	}
+	if got, want := d.limiter.Limit(), rate.Limit(1000.0/16); got != want {
+		t.Errorf("rate = %v, want %v", got, want)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	for _, tc := range []struct {
+		header   string
+		expected time.Duration
+	}{
+		{"", time.Second},
+		{"invalid", time.Second},
+		{"0", 0},
+		{"30", 30 * time.Second},
+		{"3600", maxRateLimitPause},
+		{"Wed, 21 Oct 2015 07:28:00 GMT", 0},
+	} {
+		if got := retryAfter(tc.header, time.Second); got != tc.expected {
+			t.Errorf("retryAfter(%q) = %s, want %s", tc.header, got, tc.expected)
+		}
+	}
+}
+
+func TestNewRateLimitConfig(t *testing.T) {
+	rateLimits, diags := newRateLimitConfig(deployProviderModel{})
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if rateLimits.MaxRequestsPerSecond != defaultMaxRequestsPerSecond || rateLimits.MaxConcurrentUploads != defaultMaxConcurrentUploads {
+		t.Errorf("rateLimits = %+v, want the defaults", rateLimits)
+	}
+
+	rateLimits, diags = newRateLimitConfig(deployProviderModel{
+		MaxRequestsPerSecond: types.Float64Value(2.5),
+		MaxConcurrentUploads: types.Int64Value(4),
+	})
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if rateLimits != (rateLimitConfig{MaxRequestsPerSecond: 2.5, MaxConcurrentUploads: 4}) {
+		t.Errorf("rateLimits = %+v", rateLimits)
+	}
+
+	_, diags = newRateLimitConfig(deployProviderModel{
+		MaxRequestsPerSecond: types.Float64Value(0),
+		MaxConcurrentUploads: types.Int64Value(-1),
+	})
+	if diags.ErrorsCount() != 2 {
+		t.Errorf("diagnostics = %v, want 2 errors", diags)
+	}
+}
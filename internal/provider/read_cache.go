@@ -0,0 +1,313 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"terraform-provider-deno/client"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/singleflight"
+)
+
+// readCacheTTL is how long the successful responses of GET requests are
+// reused. The provider process only lives for a single Terraform operation,
+// and the TTL bounds the staleness of the responses within a long apply.
+const readCacheTTL = 10 * time.Second
+
+type freshReadsKey struct{}
+
+// withFreshReads returns a context whose GET requests bypass the read cache,
+// for the callers waiting for a change on the API side such as the pollers.
+// Their responses still refresh the cache.
+func withFreshReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, freshReadsKey{}, true)
+}
+
+// freshReads reports whether the GET requests of the context bypass the read
+// cache.
+func freshReads(ctx context.Context) bool {
+	fresh, _ := ctx.Value(freshReadsKey{}).(bool)
+	return fresh
+}
+
+// readCacheStats are the counters of the read cache.
+type readCacheStats struct {
+	// Hits is the number of GET requests served from the cache.
+	Hits int64
+	// Coalesced is the number of GET requests that shared the response of an
+	// identical request in flight.
+	Coalesced int64
+	// Misses is the number of GET requests sent to the API.
+	Misses int64
+}
+
+// cachedResponse is a buffered response of a GET request.
+type cachedResponse struct {
+	path       string
+	status     string
+	statusCode int
+	header     http.Header
+	body       []byte
+	expires    time.Time
+}
+
+// response returns a new response of the request with the buffered content.
+func (r *cachedResponse) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        r.status,
+		StatusCode:    r.statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        r.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(r.body)),
+		ContentLength: int64(len(r.body)),
+		Request:       req,
+	}
+}
+
+// readCache is an HTTP request doer that coalesces identical GET requests in
+// flight into a single request to the Deno Deploy API, and reuses their
+// successful responses for readCacheTTL. Refreshing many resources of the same
+// domain or project thus only fetches it once.
+//
+// Any other request invalidates the cached responses of the resources it may
+// change: the ones on its path and below it, the collections listing them, and
+// the resources of the kinds embedding them.
+type readCache struct {
+	doer client.HttpRequestDoer
+	// basePath is the path of the API host, which is trimmed from the paths
+	// of the requests to tell the kind of the resources.
+	basePath string
+	ttl      time.Duration
+	now      func() time.Time
+
+	group singleflight.Group
+
+	mu      sync.Mutex
+	entries map[string]*cachedResponse
+	// generation is incremented by every invalidation, so that the responses
+	// of the requests sent before it are not cached.
+	generation uint64
+
+	hits      atomic.Int64
+	coalesced atomic.Int64
+	misses    atomic.Int64
+}
+
+// newReadCache returns a read cache sending the requests with the doer to the
+// API at basePath.
+func newReadCache(doer client.HttpRequestDoer, basePath string) *readCache {
+	return &readCache{
+		doer:     doer,
+		basePath: strings.TrimSuffix(basePath, "/"),
+		ttl:      readCacheTTL,
+		now:      time.Now,
+		entries:  map[string]*cachedResponse{},
+	}
+}
+
+// stats returns the counters of the cache.
+func (c *readCache) stats() readCacheStats {
+	return readCacheStats{
+		Hits:      c.hits.Load(),
+		Coalesced: c.coalesced.Load(),
+		Misses:    c.misses.Load(),
+	}
+}
+
+// Do sends the request, or serves it from the cache or an identical request
+// in flight if it's a GET request. A caller whose context is canceled stops
+// waiting for the request in flight, which goes on for the other callers.
+func (c *readCache) Do(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		resp, err := c.doer.Do(req)
+		// Even a failed request may have changed the resources
+		c.invalidate(c.resourcePath(req))
+		return resp, err
+	}
+
+	key := req.URL.String()
+	if !freshReads(req.Context()) {
+		if cached, ok := c.lookup(key); ok {
+			c.hits.Add(1)
+			c.record(req, "hit")
+			return cached.response(req), nil
+		}
+	}
+
+	// Requests sent after an invalidation don't join the ones sent before it
+	generation := c.currentGeneration()
+	sent := false
+	ch := c.group.DoChan(fmt.Sprintf("%d %s", generation, key), func() (any, error) {
+		sent = true
+		c.misses.Add(1)
+
+		// The request is shared by the callers joining it, so it must not be
+		// canceled along with the context of the first one
+		resp, err := c.doer.Do(req.WithContext(context.WithoutCancel(req.Context())))
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		cached := &cachedResponse{
+			path:       c.resourcePath(req),
+			status:     resp.Status,
+			statusCode: resp.StatusCode,
+			header:     resp.Header,
+			body:       body,
+			expires:    c.now().Add(c.ttl),
+		}
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			c.store(key, cached, generation)
+		}
+		return cached, nil
+	})
+
+	var result singleflight.Result
+	select {
+	case result = <-ch:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+	if result.Err != nil {
+		return nil, result.Err
+	}
+
+	if sent {
+		c.record(req, "miss")
+	} else {
+		c.coalesced.Add(1)
+		c.record(req, "coalesced")
+	}
+
+	cached, ok := result.Val.(*cachedResponse)
+	if !ok {
+		return nil, fmt.Errorf("unexpected cached value of type %T", result.Val)
+	}
+	return cached.response(req), nil
+}
+
+// record logs how the request was served along with the counters, and adds
+// it to the span of the request.
+func (c *readCache) record(req *http.Request, result string) {
+	trace.SpanFromContext(req.Context()).SetAttributes(attribute.String("deno.cache", result))
+
+	stats := c.stats()
+	tflog.SubsystemDebug(apiLogContext(req), apiLogSubsystem, "API read cache "+result, map[string]any{
+		"cache_hits":      stats.Hits,
+		"cache_coalesced": stats.Coalesced,
+		"cache_misses":    stats.Misses,
+	})
+}
+
+// resourcePath returns the path of the request relative to the API host.
+func (c *readCache) resourcePath(req *http.Request) string {
+	return strings.TrimPrefix(req.URL.Path, c.basePath)
+}
+
+// lookup returns the unexpired cached response of the key.
+func (c *readCache) lookup(key string) (*cachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cached, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !c.now().Before(cached.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return cached, true
+}
+
+// currentGeneration returns the generation of the cache.
+func (c *readCache) currentGeneration() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.generation
+}
+
+// store caches the response unless the cache was invalidated since the given
+// generation.
+func (c *readCache) store(key string, cached *cachedResponse, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.generation != generation {
+		return
+	}
+	c.entries[key] = cached
+}
+
+// invalidate removes the cached responses of the resources that a request to
+// the path may change.
+func (c *readCache) invalidate(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	for key, cached := range c.entries {
+		if affectedBy(cached.path, path) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// relatedKinds are the kinds of the resources that embed the state of
+// resources of another kind, and are thus changed along with them. A domain
+// is associated with a deployment by PATCH /domains/{id}, which changes the
+// domains of the deployment, and creating a deployment or deleting a project
+// changes the deployments the domains of the project point to.
+var relatedKinds = map[string][]string{
+	"domains":  {"deployments"},
+	"projects": {"domains"},
+}
+
+// affectedBy reports whether the resource at cachedPath may be changed by a
+// request to path. It is if either path contains the other, e.g. /domains/{id}
+// and /domains/{id}/verify, if it is a collection of the kind of the changed
+// resource, e.g. /organizations/{id}/domains for /domains/{id}, or if it is of
+// a kind related to it in relatedKinds.
+func affectedBy(cachedPath, path string) bool {
+	if hasPathPrefix(cachedPath, path) || hasPathPrefix(path, cachedPath) {
+		return true
+	}
+
+	kind := pathKind(path)
+	if strings.HasSuffix(cachedPath, "/"+kind) {
+		return true
+	}
+	return slices.Contains(relatedKinds[kind], pathKind(cachedPath))
+}
+
+// pathKind returns the kind of the resources of the path, which is its first
+// segment.
+func pathKind(path string) string {
+	kind, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	return kind
+}
+
+// hasPathPrefix reports whether the path is the prefix path or below it.
+func hasPathPrefix(path, prefix string) bool {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
@@ -0,0 +1,302 @@
+package provider
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeDoer responds to every request with its path as the body, and counts
+// the requests per method and path.
+type fakeDoer struct {
+	mu       sync.Mutex
+	requests map[string]int
+	status   int
+	// release, if not nil, blocks the requests until it's closed.
+	release chan struct{}
+}
+
+func (d *fakeDoer) Do(req *http.Request) (*http.Response, error) {
+	if d.release != nil {
+		<-d.release
+	}
+
+	d.mu.Lock()
+	if d.requests == nil {
+		d.requests = map[string]int{}
+	}
+	d.requests[req.Method+" "+req.URL.Path]++
+	d.mu.Unlock()
+
+	status := d.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(req.URL.Path)),
+	}, nil
+}
+
+func (d *fakeDoer) count(method, path string) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.requests[method+" "+path]
+}
+
+// doRequest sends the request through the cache and returns the response
+// body.
+func doRequest(t *testing.T, ctx context.Context, c *readCache, method, path string) string {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(ctx, method, "https://api.deno.com/v1"+path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("Do() returned unexpected error: %s", err)
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestReadCache_Hit(t *testing.T) {
+	doer := &fakeDoer{}
+	c := newReadCache(doer, "/v1")
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for range 3 {
+		if body := doRequest(t, ctx, c, http.MethodGet, "/domains/a"); body != "/v1/domains/a" {
+			t.Errorf("body = %q, want %q", body, "/v1/domains/a")
+		}
+	}
+	if got := doer.count(http.MethodGet, "/v1/domains/a"); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+	if got, want := c.stats(), (readCacheStats{Hits: 2, Misses: 1}); got != want {
+		t.Errorf("stats = %+v, want %+v", got, want)
+	}
+
+	now = now.Add(readCacheTTL)
+	doRequest(t, ctx, c, http.MethodGet, "/domains/a")
+	if got := doer.count(http.MethodGet, "/v1/domains/a"); got != 2 {
+		t.Errorf("requests after expiry = %d, want 2", got)
+	}
+}
+
+func TestReadCache_Coalescing(t *testing.T) {
+	doer := &fakeDoer{release: make(chan struct{})}
+	c := newReadCache(doer, "/v1")
+
+	const callers = 10
+	var wg sync.WaitGroup
+	bodies := make([]string, callers)
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, err := http.NewRequest(http.MethodGet, "https://api.deno.com/v1/domains/a", nil)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp, err := c.Do(req)
+			if err != nil {
+				t.Errorf("Do() returned unexpected error: %s", err)
+				return
+			}
+			defer resp.Body.Close()
+			b, _ := io.ReadAll(resp.Body)
+			bodies[i] = string(b)
+		}()
+	}
+	// Let the callers join the request in flight
+	time.Sleep(100 * time.Millisecond)
+	close(doer.release)
+	wg.Wait()
+
+	if got := doer.count(http.MethodGet, "/v1/domains/a"); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+	for _, body := range bodies {
+		if body != "/v1/domains/a" {
+			t.Errorf("body = %q, want %q", body, "/v1/domains/a")
+		}
+	}
+	if got, want := c.stats(), (readCacheStats{Coalesced: callers - 1, Misses: 1}); got != want {
+		t.Errorf("stats = %+v, want %+v", got, want)
+	}
+}
+
+func TestReadCache_CanceledCaller(t *testing.T) {
+	release := make(chan struct{})
+	c := newReadCache(doerFunc(func(req *http.Request) (*http.Response, error) {
+		<-release
+		if err := req.Context().Err(); err != nil {
+			return nil, err
+		}
+		return okResponse(http.StatusOK), nil
+	}), "/v1")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.deno.com/v1/domains/a", nil)
+		if err != nil {
+			first <- err
+			return
+		}
+		_, err = c.Do(req)
+		first <- err
+	}()
+	second := make(chan error, 1)
+	go func() {
+		// Join the request of the first caller
+		time.Sleep(50 * time.Millisecond)
+		req, err := http.NewRequest(http.MethodGet, "https://api.deno.com/v1/domains/a", nil)
+		if err != nil {
+			second <- err
+			return
+		}
+		resp, err := c.Do(req)
+		if err == nil {
+			resp.Body.Close()
+		}
+		second <- err
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	// The first caller returns as soon as its context is canceled
+	select {
+	case err := <-first:
+		if err != context.Canceled {
+			t.Errorf("first caller error = %v, want %v", err, context.Canceled)
+		}
+	case <-time.After(time.Second):
+		t.Error("first caller was not released by the cancellation")
+	}
+
+	close(release)
+	if err := <-second; err != nil {
+		t.Errorf("second caller returned unexpected error: %s", err)
+	}
+	if got, want := c.stats(), (readCacheStats{Coalesced: 1, Misses: 1}); got != want {
+		t.Errorf("stats = %+v, want %+v", got, want)
+	}
+}
+
+func TestReadCache_Invalidation(t *testing.T) {
+	doer := &fakeDoer{}
+	c := newReadCache(doer, "/v1")
+	ctx := context.Background()
+
+	paths := []string{"/domains/a", "/domains/b", "/organizations/o/domains", "/projects/p"}
+	for _, path := range paths {
+		doRequest(t, ctx, c, http.MethodGet, path)
+	}
+	doRequest(t, ctx, c, http.MethodPost, "/domains/a/verify")
+	for _, path := range paths {
+		doRequest(t, ctx, c, http.MethodGet, path)
+	}
+
+	for path, want := range map[string]int{
+		"/domains/a":               2,
+		"/domains/b":               1,
+		"/organizations/o/domains": 2,
+		"/projects/p":              1,
+	} {
+		if got := doer.count(http.MethodGet, "/v1"+path); got != want {
+			t.Errorf("requests of %s = %d, want %d", path, got, want)
+		}
+	}
+}
+
+func TestReadCache_InvalidationOfRelatedResources(t *testing.T) {
+	doer := &fakeDoer{}
+	c := newReadCache(doer, "/v1")
+	ctx := context.Background()
+
+	// Associating a domain with a deployment changes the deployment
+	doRequest(t, ctx, c, http.MethodGet, "/deployments/d")
+	doRequest(t, ctx, c, http.MethodGet, "/projects/p")
+	doRequest(t, ctx, c, http.MethodPatch, "/domains/a")
+	doRequest(t, ctx, c, http.MethodGet, "/deployments/d")
+	doRequest(t, ctx, c, http.MethodGet, "/projects/p")
+
+	if got := doer.count(http.MethodGet, "/v1/deployments/d"); got != 2 {
+		t.Errorf("requests of /deployments/d = %d, want 2", got)
+	}
+	if got := doer.count(http.MethodGet, "/v1/projects/p"); got != 1 {
+		t.Errorf("requests of /projects/p = %d, want 1", got)
+	}
+}
+
+func TestReadCache_FreshReads(t *testing.T) {
+	doer := &fakeDoer{}
+	c := newReadCache(doer, "/v1")
+	ctx := context.Background()
+
+	doRequest(t, ctx, c, http.MethodGet, "/domains/a")
+	doRequest(t, withFreshReads(ctx), c, http.MethodGet, "/domains/a")
+	doRequest(t, ctx, c, http.MethodGet, "/domains/a")
+
+	if got := doer.count(http.MethodGet, "/v1/domains/a"); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+	if got, want := c.stats(), (readCacheStats{Hits: 1, Misses: 2}); got != want {
+		t.Errorf("stats = %+v, want %+v", got, want)
+	}
+}
+
+func TestReadCache_ErrorNotCached(t *testing.T) {
+	doer := &fakeDoer{status: http.StatusNotFound}
+	c := newReadCache(doer, "/v1")
+	ctx := context.Background()
+
+	doRequest(t, ctx, c, http.MethodGet, "/domains/a")
+	doRequest(t, ctx, c, http.MethodGet, "/domains/a")
+
+	if got := doer.count(http.MethodGet, "/v1/domains/a"); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+}
+
+func TestAffectedBy(t *testing.T) {
+	for _, tc := range []struct {
+		cachedPath string
+		path       string
+		expected   bool
+	}{
+		{"/domains/a", "/domains/a", true},
+		{"/domains/a", "/domains/a/verify", true},
+		{"/domains/a/certificates", "/domains/a", true},
+		{"/domains/ab", "/domains/a", false},
+		{"/domains/b", "/domains/a", false},
+		{"/organizations/o/domains", "/domains/a", true},
+		{"/organizations/o/projects", "/domains/a", false},
+		{"/projects/p", "/projects/p/deployments", true},
+		{"/deployments/d", "/projects/p/deployments", false},
+		{"/deployments/d", "/domains/a", true},
+		{"/domains/a", "/projects/p", true},
+		{"/projects/p", "/domains/a", false},
+	} {
+		if got := affectedBy(tc.cachedPath, tc.path); got != tc.expected {
+			t.Errorf("affectedBy(%q, %q) = %v, want %v", tc.cachedPath, tc.path, got, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"terraform-provider-deno/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the name of the tracer and the service of the spans.
+const tracerName = "terraform-provider-deno"
+
+// SetupTracing installs the OpenTelemetry tracer provider exporting the spans
+// to the OTLP endpoint of the OTEL_EXPORTER_OTLP_ENDPOINT environment
+// variable, along with the W3C trace context propagator. The exporter is
+// configured by the other OTEL_EXPORTER_OTLP_* environment variables.
+//
+// Tracing is disabled if OTEL_EXPORTER_OTLP_ENDPOINT is not set. The returned
+// function flushes the pending spans and must be called before exiting.
+func SetupTracing(ctx context.Context, version string) (func(context.Context) error, error) {
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not create the OTLP trace exporter: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(sdkresource.NewSchemaless(
+			attribute.String("service.name", tracerName),
+			attribute.String("service.version", version),
+		)),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return tp.Shutdown, nil
+}
+
+// startSpan starts a span as a child of the span in the context. It's a no-op
+// span unless tracing is set up.
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan ends the span, marking it as failed if the diagnostics have errors.
+// It takes a pointer so that it can be deferred before the diagnostics are
+// appended.
+func endSpan(span trace.Span, diags *diag.Diagnostics) {
+	if errs := diags.Errors(); len(errs) > 0 {
+		for _, d := range errs {
+			span.RecordError(errors.New(d.Summary() + ": " + d.Detail()))
+		}
+		span.SetStatus(codes.Error, errs[0].Summary())
+	}
+	span.End()
+}
+
+// tracingDoer is an HTTP request doer that records a client span for each
+// request to the Deno Deploy API, and propagates its trace context in the
+// traceparent header so that the API can correlate the request.
+type tracingDoer struct {
+	doer client.HttpRequestDoer
+}
+
+// Do sends the request within a span.
+func (d *tracingDoer) Do(req *http.Request) (*http.Response, error) {
+	ctx, span := otel.Tracer(tracerName).Start(req.Context(), "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("url.full", req.URL.String()),
+			attribute.String("server.address", req.URL.Hostname()),
+		),
+	)
+	defer span.End()
+
+	req = req.WithContext(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := d.doer.Do(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if ray := resp.Header.Get(client.X_DENO_RAY); ray != "" {
+		span.SetAttributes(attribute.String("deno.ray", ray))
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	return resp, nil
+}
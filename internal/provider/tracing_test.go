@@ -0,0 +1,203 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordSpans installs a tracer provider recording the spans in memory and
+// the trace context propagator for the duration of the test.
+func recordSpans(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+		_ = tp.Shutdown(context.Background())
+	})
+
+	return exporter
+}
+
+// spanAttribute returns the value of the attribute of the span.
+func spanAttribute(span tracetest.SpanStub, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestSetupTracing_Disabled(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	prevProvider := otel.GetTracerProvider()
+
+	shutdown, err := SetupTracing(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("SetupTracing() returned unexpected error: %s", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown() returned unexpected error: %s", err)
+	}
+	if otel.GetTracerProvider() != prevProvider {
+		t.Error("tracer provider should not be replaced when tracing is disabled")
+	}
+}
+
+func TestTracingDoer(t *testing.T) {
+	exporter := recordSpans(t)
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ctx, parent := startSpan(context.Background(), "parent")
+	doer := &tracingDoer{doer: server.Client()}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/projects", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := doer.Do(req)
+	if err != nil {
+		t.Fatalf("Do() returned unexpected error: %s", err)
+	}
+	resp.Body.Close()
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/missing", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err = doer.Do(req)
+	if err != nil {
+		t.Fatalf("Do() returned unexpected error: %s", err)
+	}
+	resp.Body.Close()
+	parent.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 3 {
+		t.Fatalf("got %d spans, want 3", len(spans))
+	}
+	ok, notFound := spans[0], spans[1]
+
+	if ok.Name != "HTTP GET" {
+		t.Errorf("span name = %q, want %q", ok.Name, "HTTP GET")
+	}
+	if ok.Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Error("request span should be a child of the span in the request context")
+	}
+	if got := spanAttribute(ok, "http.response.status_code").AsInt64(); got != http.StatusOK {
+		t.Errorf("status code attribute = %d, want %d", got, http.StatusOK)
+	}
+	if ok.Status.Code != codes.Unset {
+		t.Errorf("status of the successful request = %v, want unset", ok.Status.Code)
+	}
+	if notFound.Status.Code != codes.Error {
+		t.Errorf("status of the failed request = %v, want error", notFound.Status.Code)
+	}
+
+	want := "00-" + notFound.SpanContext.TraceID().String() + "-" + notFound.SpanContext.SpanID().String() + "-01"
+	if traceparent != want {
+		t.Errorf("traceparent = %q, want %q", traceparent, want)
+	}
+}
+
+func TestPollerSpans(t *testing.T) {
+	exporter := recordSpans(t)
+
+	p := &poller{Interval: time.Second, clock: &fakeClock{}}
+	err := p.poll(context.Background(), func(_ context.Context, attempt int) (bool, error) {
+		if attempt == 3 {
+			return false, errors.New("boom")
+		}
+		return false, nil
+	})
+	if err == nil {
+		t.Fatal("poll() should return the error")
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 3 {
+		t.Fatalf("got %d spans, want one per attempt", len(spans))
+	}
+	for i, span := range spans {
+		if span.Name != "poll" {
+			t.Errorf("span name = %q, want %q", span.Name, "poll")
+		}
+		if got := spanAttribute(span, "attempt").AsInt64(); got != int64(i+1) {
+			t.Errorf("attempt attribute = %d, want %d", got, i+1)
+		}
+	}
+	if spans[2].Status.Code != codes.Error {
+		t.Errorf("status of the failed attempt = %v, want error", spans[2].Status.Code)
+	}
+}
+
+func TestPrepareAssetsForUploadSpan(t *testing.T) {
+	exporter := recordSpans(t)
+
+	_, d := prepareAssetsForUpload(context.Background(), map[string]asset{
+		"main.ts": {Kind: types.StringValue("file"), Content: types.StringValue("console.log(1)")},
+	})
+	if d != nil {
+		t.Fatalf("prepareAssetsForUpload() returned unexpected diagnostic: %v", d)
+	}
+	_, d = prepareAssetsForUpload(context.Background(), map[string]asset{})
+	if d == nil {
+		t.Fatal("prepareAssetsForUpload() should fail without assets")
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	if got := spanAttribute(spans[0], "assets").AsInt64(); got != 1 {
+		t.Errorf("assets attribute = %d, want 1", got)
+	}
+	if spans[0].Status.Code != codes.Unset || spans[1].Status.Code != codes.Error {
+		t.Errorf("statuses = %v, %v, want unset, error", spans[0].Status.Code, spans[1].Status.Code)
+	}
+}
+
+func TestResourceCRUDSpan(t *testing.T) {
+	exporter := recordSpans(t)
+
+	resp := deleteResource(t, &certificateProvisioningResource{}, map[string]any{
+		"domain_id":           uuid.NewString(),
+		"provisioning_status": "success",
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 || spans[0].Name != "deno_domain_certificate.Delete" {
+		t.Fatalf("spans = %v, want a single deno_domain_certificate.Delete span", spans)
+	}
+}
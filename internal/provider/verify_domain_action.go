@@ -0,0 +1,182 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-deno/client"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ action.Action                   = &verifyDomainAction{}
+	_ action.ActionWithConfigure      = &verifyDomainAction{}
+	_ action.ActionWithValidateConfig = &verifyDomainAction{}
+)
+
+// NewVerifyDomainAction is a helper function to simplify the provider implementation.
+func NewVerifyDomainAction() action.Action {
+	return &verifyDomainAction{}
+}
+
+// verifyDomainAction is the action implementation.
+type verifyDomainAction struct {
+	client         client.ClientWithResponsesInterface
+	organizationID uuid.UUID
+}
+
+// verifyDomainActionModel maps the action schema data.
+type verifyDomainActionModel struct {
+	DomainID          types.String  `tfsdk:"domain_id"`
+	DNSResolver       types.String  `tfsdk:"dns_resolver"`
+	SkipDNSPrecheck   types.Bool    `tfsdk:"skip_dns_precheck"`
+	PollInterval      types.String  `tfsdk:"poll_interval"`
+	MaxPollInterval   types.String  `tfsdk:"max_poll_interval"`
+	BackoffMultiplier types.Float64 `tfsdk:"backoff_multiplier"`
+	Timeout           types.String  `tfsdk:"timeout"`
+}
+
+// Metadata returns the action type name.
+func (a *verifyDomainAction) Metadata(_ context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_verify_domain"
+}
+
+// Schema defines the schema for the action.
+func (a *verifyDomainAction) Schema(_ context.Context, _ action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: `
+Verifies the ownership of a custom domain, waiting until Deno Deploy accepts the verification.
+
+This does the same as deno_domain_verification resource without keeping anything in the Terraform state, so it can be run again with ` + "`terraform apply -invoke=action.deno_verify_domain.<name>`" + ` e.g. after fixing the DNS records. The progress of each verification attempt is reported while waiting.
+		`,
+		Attributes: map[string]schema.Attribute{
+			"domain_id": schema.StringAttribute{
+				Required:    true,
+				Description: "The ID of the domain to verify ownership of.",
+			},
+			"dns_resolver": schema.StringAttribute{
+				Optional:    true,
+				Description: "The address of the DNS resolver used to check the DNS records before the verification, in `host:port` form such as `1.1.1.1:53`. Defaults to the resolver of the system.",
+			},
+			"skip_dns_precheck": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether to skip checking the DNS records locally before the verification. Defaults to `false`.",
+			},
+			"poll_interval": schema.StringAttribute{
+				Optional:    true,
+				Description: "The time to wait after the first verification attempt, such as `10s`. Defaults to `5s`.",
+			},
+			"max_poll_interval": schema.StringAttribute{
+				Optional:    true,
+				Description: "The maximum time to wait between verification attempts, such as `2m`. Defaults to `1m`, or `poll_interval` if it is longer.",
+			},
+			"backoff_multiplier": schema.Float64Attribute{
+				Optional:    true,
+				Description: "The factor by which the time between verification attempts grows after each attempt, up to `max_poll_interval`. Defaults to `1.5`.",
+			},
+			"timeout": schema.StringAttribute{
+				Optional:    true,
+				Description: "The maximum time to wait for the verification, such as `30m`. Defaults to `10m`.",
+			},
+		},
+	}
+}
+
+// ValidateConfig validates the polling attributes and the timeout at plan time.
+func (a *verifyDomainAction) ValidateConfig(ctx context.Context, req action.ValidateConfigRequest, resp *action.ValidateConfigResponse) {
+	var config verifyDomainActionModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, diags = newPollerFromAttributes(config.PollInterval, config.MaxPollInterval, config.BackoffMultiplier)
+	resp.Diagnostics.Append(diags...)
+	_, diags = parseActionTimeout(config.Timeout, defaultDomainVerificationTimeout)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Invoke verifies the domain, streaming the progress to Terraform.
+func (a *verifyDomainAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var config verifyDomainActionModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := parseActionTimeout(config.Timeout, defaultDomainVerificationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	summary := fmt.Sprintf("Unable to Verify Domain %s", config.DomainID.ValueString())
+	domainID, err := uuid.Parse(config.DomainID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			summary,
+			fmt.Sprintf("Could not parse domain ID %s: %s", config.DomainID, err.Error()),
+		)
+		return
+	}
+
+	poller, diags := newPollerFromAttributes(config.PollInterval, config.MaxPollInterval, config.BackoffMultiplier)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = withProgress(ctx, resp.SendProgress)
+	verification := &domainVerificationResource{client: a.client, organizationID: a.organizationID}
+
+	// Check the DNS records before asking for the verification
+	diags = verification.precheckDNS(ctx, domainVerificationResourceModel{
+		DomainID:        config.DomainID,
+		DNSResolver:     config.DNSResolver,
+		SkipDNSPrecheck: config.SkipDNSPrecheck,
+	}, domainID, summary)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	reportProgress(ctx, "Verifying domain %s", domainID)
+	diags = verification.waitForVerification(ctx, poller, domainID, timeout, summary)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	reportProgress(ctx, "Domain %s is verified", domainID)
+}
+
+// Configure adds the provider configured client to the action.
+func (a *verifyDomainAction) Configure(_ context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*deployProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Action Configure Type",
+			fmt.Sprintf("Expected *deployProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	resp.Diagnostics.Append(providerData.connect()...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	a.client = providerData.client
+	a.organizationID = providerData.organizationID
+}
@@ -0,0 +1,95 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"terraform-provider-deno/client"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+)
+
+// fakeVerificationClient fails the given number of verification attempts
+// before succeeding.
+type fakeVerificationClient struct {
+	client.ClientWithResponsesInterface
+
+	failures int
+	calls    int
+}
+
+func (c *fakeVerificationClient) VerifyDomainWithResponse(_ context.Context, _ uuid.UUID, _ ...client.RequestEditorFn) (*client.VerifyDomainResponse, error) {
+	c.calls++
+	if c.calls <= c.failures {
+		return &client.VerifyDomainResponse{
+			HTTPResponse: &http.Response{StatusCode: http.StatusBadRequest},
+			Body:         []byte(`{"code":"domainNotVerified"}`),
+		}, nil
+	}
+	return &client.VerifyDomainResponse{HTTPResponse: &http.Response{StatusCode: http.StatusOK}}, nil
+}
+
+func TestVerifyDomainAction(t *testing.T) {
+	fake := &fakeVerificationClient{failures: 2}
+	a := &verifyDomainAction{client: fake}
+	domainID := uuid.NewString()
+
+	messages, diags := invokeAction(t, a, map[string]any{
+		"domain_id":         domainID,
+		"skip_dns_precheck": true,
+		"poll_interval":     "1ms",
+	})
+	if diags.HasError() {
+		t.Fatalf("Invoke() returned unexpected diagnostics: %v", diags)
+	}
+	if fake.calls != 3 {
+		t.Errorf("calls = %d, want 3", fake.calls)
+	}
+
+	// One message before verifying, one per failed attempt and one at the end
+	if len(messages) != 4 {
+		t.Fatalf("messages = %q, want 4", messages)
+	}
+	if !strings.Contains(messages[1], "not verified yet (attempt 1)") {
+		t.Errorf("messages[1] = %q, want the first failed attempt", messages[1])
+	}
+	if messages[3] != "Domain "+domainID+" is verified" {
+		t.Errorf("messages[3] = %q", messages[3])
+	}
+}
+
+func TestVerifyDomainAction_Timeout(t *testing.T) {
+	fake := &fakeVerificationClient{failures: 1 << 30}
+	a := &verifyDomainAction{client: fake}
+
+	_, diags := invokeAction(t, a, map[string]any{
+		"domain_id":         uuid.NewString(),
+		"skip_dns_precheck": true,
+		"poll_interval":     "1ms",
+		"timeout":           "20ms",
+	})
+	if !diags.HasError() {
+		t.Fatal("Invoke() should time out")
+	}
+	if !strings.Contains(diags[0].Detail(), "Timed out after 20ms") {
+		t.Errorf("unexpected detail: %s", diags[0].Detail())
+	}
+}
+
+func TestVerifyDomainAction_ValidateConfig(t *testing.T) {
+	a := &verifyDomainAction{}
+	resp := &action.ValidateConfigResponse{}
+	a.ValidateConfig(context.Background(), action.ValidateConfigRequest{
+		Config: actionConfig(t, a, map[string]any{
+			"domain_id":          uuid.NewString(),
+			"backoff_multiplier": 0.5,
+			"timeout":            "never",
+		}),
+	}, resp)
+
+	if resp.Diagnostics.ErrorsCount() != 2 {
+		t.Errorf("diagnostics = %v, want errors for backoff_multiplier and timeout", resp.Diagnostics)
+	}
+}